@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package base
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Warning categories. Every warning belongs to exactly one category, and each
+// category may be enabled or suppressed individually.
+const (
+	WarnUnused    = "unused"    // local constants and types which are never used
+	WarnShadow    = "shadow"    // declarations hiding another in an outer scope
+	WarnPrecision = "precision" // constants rounded to float32
+)
+
+// warnings maps all known warning categories to whether they are reported.
+// Shadowing is mostly deliberate, so it is only reported on request.
+var warnings = map[string]bool{
+	WarnUnused:    true,
+	WarnShadow:    false,
+	WarnPrecision: true,
+}
+
+// IsWarning reports whether category is a known warning category.
+func IsWarning(category string) bool {
+	_, ok := warnings[category]
+	return ok
+}
+
+// WarningEnabled reports whether warnings of the given category are reported.
+func WarningEnabled(category string) bool {
+	enabled, ok := warnings[category]
+	if !ok {
+		Fatalf("unknown warning category %q", category)
+	}
+	return enabled
+}
+
+// EnableWarnings reports all warnings of the categories in list, which is a
+// comma-separated list of category names, such as "shadow".
+func EnableWarnings(list string) error {
+	return setWarnings(list, true)
+}
+
+// SuppressWarnings suppresses all warnings of the categories in list, which is
+// a comma-separated list of category names, such as "unused,shadow".
+func SuppressWarnings(list string) error {
+	return setWarnings(list, false)
+}
+
+func setWarnings(list string, enabled bool) error {
+	for _, category := range strings.Split(list, ",") {
+		if _, ok := warnings[category]; !ok {
+			return fmt.Errorf("unknown warning category %q", category)
+		}
+		warnings[category] = enabled
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package base
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestSuppressWarnings(t *testing.T) {
+	defer func(saved map[string]bool) { warnings = saved }(maps.Clone(warnings))
+
+	if err := SuppressWarnings("unused,precision"); err != nil {
+		t.Fatal(err)
+	}
+	for category := range warnings {
+		want := category != WarnUnused && category != WarnPrecision && category != WarnShadow
+		if got := WarningEnabled(category); got != want {
+			t.Errorf("WarningEnabled(%q) = %v, want %v", category, got, want)
+		}
+	}
+
+	if err := SuppressWarnings("unused,bogus"); err == nil || err.Error() != `unknown warning category "bogus"` {
+		t.Errorf("got error %v, want unknown warning category \"bogus\"", err)
+	}
+	if !IsWarning(WarnPrecision) || IsWarning("bogus") {
+		t.Errorf("IsWarning does not match the known categories")
+	}
+}
+
+func TestEnableWarnings(t *testing.T) {
+	defer func(saved map[string]bool) { warnings = saved }(maps.Clone(warnings))
+
+	// shadowing warnings are opt-in
+	if WarningEnabled(WarnShadow) {
+		t.Errorf("shadow warnings enabled by default")
+	}
+	if err := EnableWarnings("shadow"); err != nil {
+		t.Fatal(err)
+	}
+	if !WarningEnabled(WarnShadow) {
+		t.Errorf("shadow warnings not enabled")
+	}
+	if err := SuppressWarnings("shadow"); err != nil || WarningEnabled(WarnShadow) {
+		t.Errorf("shadow warnings not suppressed again: %v", err)
+	}
+	if err := EnableWarnings("bogus"); err == nil || err.Error() != `unknown warning category "bogus"` {
+		t.Errorf("got error %v, want unknown warning category \"bogus\"", err)
+	}
+}
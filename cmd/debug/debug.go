@@ -4,6 +4,19 @@
 
 package debug
 
+import (
+	"os"
+	"strconv"
+)
+
 // Enabled controls whether or not debugging features are enabled. If not, all
 // debugging functions/methods are no-ops.
-const Enabled bool = true
+//
+// It is set from the COBALT_DEBUG environment variable at startup, which
+// accepts the same values as [strconv.ParseBool], such as COBALT_DEBUG=1.
+// Debugging is disabled if the variable is unset or invalid.
+var Enabled bool
+
+func init() {
+	Enabled, _ = strconv.ParseBool(os.Getenv("COBALT_DEBUG"))
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package debug
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// As Enabled is set when the package is initialized, TestEnvironment runs the
+// test binary again in a child process with COBALT_DEBUG set.
+func TestEnvironment(t *testing.T) {
+	if os.Getenv("COBALT_DEBUG_TEST_CHILD") != "" {
+		Assert(false, "child")
+		return
+	}
+
+	for _, test := range []struct {
+		env  string
+		fail bool
+	}{
+		{"", false},
+		{"0", false},
+		{"invalid", false},
+		{"1", true},
+		{"true", true},
+	} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestEnvironment$")
+		cmd.Env = append(os.Environ(), "COBALT_DEBUG_TEST_CHILD=1", "COBALT_DEBUG="+test.env)
+		out, err := cmd.CombinedOutput()
+		failed := strings.Contains(string(out), "internal error: assertion failed: child")
+		if failed != test.fail || (err != nil) != test.fail {
+			t.Errorf("COBALT_DEBUG=%s: got failure %v (%v), want %v\n%s", test.env, failed, err, test.fail, out)
+		}
+	}
+}
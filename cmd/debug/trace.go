@@ -9,13 +9,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"runtime"
+	"strings"
 	"sync"
 )
 
 var (
 	traceIndent []byte
 	traceOutput io.Writer = os.Stdout
+	traceFilter string
 	traceLock   sync.Mutex
 )
 
@@ -36,25 +39,74 @@ func Trace() func() {
 		base.Fatalf("could not get caller information")
 	}
 
+	name := runtime.FuncForPC(pc).Name()
+
 	traceLock.Lock()
-	fmt.Fprintf(traceOutput, " %s%s() {\n", traceIndent, runtime.FuncForPC(pc).Name())
+	defer traceLock.Unlock()
+
+	// filtered frames neither print nor indent, so there is nothing to undo
+	if !traceMatch(name) {
+		return func() {}
+	}
+
+	fmt.Fprintf(traceOutput, " %s%s() {\n", traceIndent, name)
 	traceIndent = append(traceIndent, traceTab...)
-	traceLock.Unlock()
 
 	return untrace
 }
 
 func untrace() {
+	traceLock.Lock()
 	traceIndent = traceIndent[:len(traceIndent)-len(traceTab)]
 	fmt.Fprintf(traceOutput, " %s}\n", traceIndent)
+	traceLock.Unlock()
+}
+
+// TraceFilter restricts tracing to the functions whose name matches pattern,
+// and returns the previous pattern. If pattern contains any of the characters
+// "*?[", it is a glob pattern as described by [path.Match], which is matched
+// against the function name without its package path, as in
+// "syntax.(*parser).binaryExpr". Otherwise, a function matches if its full name
+// contains pattern. An empty pattern matches all functions.
+func TraceFilter(pattern string) (old string) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		base.Fatalf("invalid trace filter %q: %v", pattern, err)
+	}
+
+	traceLock.Lock()
+	old, traceFilter = traceFilter, pattern
+	traceLock.Unlock()
+	return
+}
+
+// traceMatch reports whether the function name matches the trace filter.
+// The caller must hold traceLock.
+func traceMatch(name string) bool {
+	if !strings.ContainsAny(traceFilter, "*?[") {
+		return strings.Contains(name, traceFilter)
+	}
+
+	name = name[strings.LastIndexByte(name, '/')+1:]
+	ok, _ := path.Match(traceFilter, name)
+	return ok
 }
 
 // TraceOutput sets the tracing output to the provided writer and returns the
 // previous one. If w == nil, then the output writer will remain unchanged.
-func TraceOuput(w io.Writer) (old io.Writer) {
+func TraceOutput(w io.Writer) (old io.Writer) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+
 	old = traceOutput
 	if w != nil {
 		traceOutput = w
 	}
 	return
 }
+
+// TraceOuput is a misspelling of TraceOutput.
+//
+// Deprecated: Use [TraceOutput] instead.
+func TraceOuput(w io.Writer) (old io.Writer) {
+	return TraceOutput(w)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package debug
+
+import (
+	"bytes"
+	"testing"
+)
+
+func traceOuter() {
+	defer Trace()()
+	traceInner()
+}
+
+func traceInner() {
+	defer Trace()()
+	traceLeaf()
+}
+
+func traceLeaf() {
+	defer Trace()()
+}
+
+// traceCalls traces a call of traceOuter with the provided filter, and returns
+// the output.
+func traceCalls(filter string) string {
+	defer func(enabled bool) { Enabled = enabled }(Enabled)
+	Enabled = true
+
+	var buf bytes.Buffer
+	defer TraceOutput(TraceOutput(&buf))
+	defer TraceFilter(TraceFilter(filter))
+
+	traceOuter()
+	return buf.String()
+}
+
+func TestTraceFilter(t *testing.T) {
+	for _, test := range []struct{ filter, want string }{
+		{"", " cobalt/debug.traceOuter() {\n . cobalt/debug.traceInner() {\n . . cobalt/debug.traceLeaf() {\n . . }\n . }\n }\n"},
+		{"Inner", " cobalt/debug.traceInner() {\n }\n"},
+		{"debug.trace[OL]*", " cobalt/debug.traceOuter() {\n . cobalt/debug.traceLeaf() {\n . }\n }\n"},
+		{"nomatch", ""},
+	} {
+		if got := traceCalls(test.filter); got != test.want {
+			t.Errorf("filter %q: got\n%s\nwant\n%s", test.filter, got, test.want)
+		}
+	}
+
+	// the indentation is balanced after filtered frames
+	if len(traceIndent) != 0 {
+		t.Errorf("got indentation %q after tracing, want none", traceIndent)
+	}
+}
+
+func TestTraceOutput(t *testing.T) {
+	var a, b bytes.Buffer
+	old := TraceOutput(&a)
+	defer TraceOutput(old)
+
+	if got := TraceOutput(nil); got != &a {
+		t.Errorf("TraceOutput(nil) returned %v, want the current writer", got)
+	}
+	if got := TraceOuput(&b); got != &a {
+		t.Errorf("TraceOuput returned %v, want the previous writer", got)
+	}
+	if got := TraceOutput(old); got != &b {
+		t.Errorf("TraceOutput returned %v, want the writer set by TraceOuput", got)
+	}
+}
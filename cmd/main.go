@@ -7,20 +7,70 @@ package main
 import (
 	"cobalt/base"
 	"cobalt/syntax"
+	"cobalt/types"
+	"flag"
 	"fmt"
 	"os"
 )
 
+var (
+	w        = flag.String("w", "", "report warnings of the comma-separated `categories`, such as shadow")
+	wno      = flag.String("wno", "", "suppress warnings of the comma-separated `categories`")
+	tabwidth = flag.Int("tabwidth", 1, "number of `columns` between tab stops in positions")
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: co [flags] <file.co>...")
+	flag.PrintDefaults()
+	os.Exit(1)
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Usage: co <file.co>")
-		os.Exit(1)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+	}
+	if *w != "" {
+		if err := base.EnableWarnings(*w); err != nil {
+			base.Errorf("%v", err)
+		}
+	}
+	if *wno != "" {
+		if err := base.SuppressWarnings(*wno); err != nil {
+			base.Errorf("%v", err)
+		}
 	}
 
-	file, err := syntax.ParseFile(os.Args[1])
+	if *tabwidth < 1 {
+		base.Errorf("invalid tab width %d", *tabwidth)
+	}
+	conf := syntax.Config{TabWidth: *tabwidth}
+
+	files, err := conf.ParseFiles(flag.Args())
 	if err != nil {
-		base.Errorf("%v", err)
+		// the errors of all files are joined
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+		base.Exit(1)
 	}
 
-	_ = file
+	types.PtrSize = 8
+	types.Init()
+
+	mod := types.NewModule("main", flag.Arg(0))
+	sum := types.CheckFiles(mod, files)
+	for _, err := range sum.Errors {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	}
+	for _, warn := range sum.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %v [%s]\n", warn, warn.Category)
+	}
+	if len(sum.Errors) > 0 || len(sum.Warnings) > 0 {
+		fmt.Fprintln(os.Stderr, sum)
+	}
+	if len(sum.Errors) > 0 {
+		base.Exit(1)
+	}
 }
@@ -8,7 +8,10 @@
 package src
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -36,7 +39,14 @@ func (p Pos) Known() bool {
 // MakePos creates a new Pos value with the provided file name, line-, and
 // column numbers. There is a hard limit for line- and column numbers, defined
 // by LineMax and ColMax respectively.
+//
+// As line and column numbers start at 1, a zero line or column number denotes
+// an unknown line or column. For example, MakePos(f, 3, 0) is rendered as f:3.
+// A column is meaningless without a line, so col is ignored if line is zero.
 func MakePos(filename string, line uint, col uint) Pos {
+	if line == 0 {
+		col = 0
+	}
 	return Pos{
 		index: insert(filename),
 		lico:  lico(line, col),
@@ -57,6 +67,19 @@ func (p Pos) After(q Pos) bool {
 	return p.index != 0 && p.index == q.index && p.lico > q.lico
 }
 
+// Compare returns -1, 0 or +1 depending on whether p is ordered before, at
+// the same position as, or after q. Contrary to Before and After, positions in
+// different source files are ordered too, by file name first. Unknown
+// positions are ordered before all known ones.
+func Compare(p, q Pos) int {
+	if p.index != q.index {
+		if c := strings.Compare(p.Filename(), q.Filename()); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(p.lico, q.lico)
+}
+
 // Filename returns the file name for p. If p has no source file, Filename
 // returns an empty string.
 func (p Pos) Filename() string {
@@ -78,16 +101,40 @@ func (p Pos) Col() uint {
 // String returns a string representation of p. If p has no associated source
 // file, String returns "<unknown position>".
 func (p Pos) String() string {
+	return p.Format(1, 1)
+}
+
+// MarshalText implements [encoding.TextMarshaler], such that p is encoded as
+// its string representation, for example in JSON.
+func (p Pos) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// Format is like String, but renders the line and column numbers starting from
+// linebase and colbase respectively, rather than from 1. For example, tools
+// that expect zero-based positions may use p.Format(0, 0). This only affects
+// the rendering of p, not the line and column numbers stored in p.
+func (p Pos) Format(linebase, colbase uint) string {
 	if p.index == 0 {
 		return "<unknown position>"
 	}
 	if p.Line() == 0 {
 		return lookup(p.index) // file
 	}
+	line := p.Line() - 1 + linebase
 	if p.Col() == 0 {
-		return fmt.Sprintf("%s:%d", lookup(p.index), p.Line()) // file:line
+		return fmt.Sprintf("%s:%d", lookup(p.index), line) // file:line
 	}
-	return fmt.Sprintf("%s:%d:%d", lookup(p.index), p.Line(), p.Col()) // file:line:col
+	col := p.Col() - 1 + colbase
+	return fmt.Sprintf("%s:%d:%d", lookup(p.index), line, col) // file:line:col
+}
+
+// Files returns the names of all source files positions have been created
+// for, in the order they were first seen.
+func Files() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(namelist)
 }
 
 // ----------------------------------------------------------------------------
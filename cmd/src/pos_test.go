@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package src
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	for _, test := range []struct {
+		pos            Pos
+		one, zero, str string
+	}{
+		{MakePos("a.co", 3, 7), "a.co:3:7", "a.co:2:6", "a.co:3:7"},
+		{MakePos("a.co", 1, 1), "a.co:1:1", "a.co:0:0", "a.co:1:1"},
+		{MakePos("a.co", 3, 0), "a.co:3", "a.co:2", "a.co:3"},
+		{MakePos("a.co", 0, 0), "a.co", "a.co", "a.co"},
+		{NoPos, "<unknown position>", "<unknown position>", "<unknown position>"},
+	} {
+		if got := test.pos.Format(1, 1); got != test.one {
+			t.Errorf("Format(1, 1) = %s, want %s", got, test.one)
+		}
+		if got := test.pos.Format(0, 0); got != test.zero {
+			t.Errorf("Format(0, 0) = %s, want %s", got, test.zero)
+		}
+		if got := test.pos.String(); got != test.str {
+			t.Errorf("String() = %s, want %s", got, test.str)
+		}
+	}
+
+	// the stored position is unaffected
+	p := MakePos("a.co", 3, 7)
+	p.Format(0, 0)
+	if p.Line() != 3 || p.Col() != 7 {
+		t.Errorf("got line %d, col %d, want 3, 7", p.Line(), p.Col())
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a1, a2 := MakePos("a.co", 1, 5), MakePos("a.co", 2, 1)
+	b1 := MakePos("b.co", 1, 1)
+	for _, test := range []struct {
+		p, q Pos
+		want int
+	}{
+		{a1, a1, 0},
+		{a1, a2, -1},
+		{a2, a1, +1},
+		{MakePos("a.co", 1, 4), a1, -1},
+		// across files, by file name first
+		{a2, b1, -1},
+		{b1, a2, +1},
+		{MakePos("b.co", 1, 1), b1, 0},
+		// unknown positions come first
+		{NoPos, a1, -1},
+		{a1, NoPos, +1},
+		{NoPos, NoPos, 0},
+	} {
+		if got := Compare(test.p, test.q); got != test.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", test.p, test.q, got, test.want)
+		}
+	}
+}
+
+func TestClamping(t *testing.T) {
+	for _, test := range []struct {
+		pos       Pos
+		line, col uint
+		str       string
+	}{
+		{MakePos("a.co", 1, ColMax), 1, ColMax, fmt.Sprintf("a.co:1:%d", ColMax)},
+		{MakePos("a.co", 1, ColMax+1), 1, ColMax, fmt.Sprintf("a.co:1:%d", ColMax)},
+		{MakePos("a.co", LineMax+1, 2), LineMax, 2, fmt.Sprintf("a.co:%d:2", LineMax)},
+		{MakePos("a.co", 3, 0), 3, 0, "a.co:3"}, // an unknown column
+		{MakePos("a.co", 0, 5), 0, 0, "a.co"},   // a column without line
+	} {
+		if test.pos.Line() != test.line || test.pos.Col() != test.col {
+			t.Errorf("%s: got line %d, col %d, want %d, %d", test.pos, test.pos.Line(), test.pos.Col(), test.line, test.col)
+		}
+		if got := test.pos.String(); got != test.str {
+			t.Errorf("got %s, want %s", got, test.str)
+		}
+	}
+
+	// clamped positions compare equal to the maximum, and still order correctly
+	p, q := MakePos("a.co", 1, ColMax), MakePos("a.co", 1, ColMax+1)
+	if p.Before(q) || p.After(q) || Compare(p, q) != 0 {
+		t.Errorf("%s and the clamped %s are ordered", p, q)
+	}
+	if next := MakePos("a.co", 2, 1); !q.Before(next) || !next.After(q) {
+		t.Errorf("%s is not before %s", q, next)
+	}
+	if col0 := MakePos("a.co", 1, 0); !col0.Before(MakePos("a.co", 1, 1)) {
+		t.Errorf("a position without column is not before column 1")
+	}
+}
+
+func TestFiles(t *testing.T) {
+	// other tests register files too, so only the new ones are compared
+	n := len(Files())
+	for _, name := range []string{"files/c.co", "files/a.co", "files/b.co", "files/a.co"} {
+		MakePos(name, 1, 1)
+	}
+	got := Files()[n:]
+	if want := []string{"files/c.co", "files/a.co", "files/b.co"}; !slices.Equal(got, want) {
+		t.Errorf("got files %q, want %q", got, want)
+	}
+
+	// the result is a copy
+	Files()[0] = "x"
+	if Files()[0] == "x" {
+		t.Errorf("Files returned the internal table")
+	}
+}
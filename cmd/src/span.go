@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package src
+
+import "fmt"
+
+// A Span is a range of source code, from the byte at Start up to and including
+// the byte at End. Both positions should be in the same source file. A zero
+// Span is considered unknown, like a zero Pos.
+type Span struct {
+	Start, End Pos
+}
+
+// MakeSpan creates a new Span ranging from start to end.
+func MakeSpan(start, end Pos) Span {
+	return Span{start, end}
+}
+
+// Known reports whether s is considered a known span.
+func (s Span) Known() bool {
+	return s.Start.Known() && s.End.Known()
+}
+
+// Contains reports whether p lies within s. It reports false if either s or p
+// are unknown, or if p is from a different source file.
+func (s Span) Contains(p Pos) bool {
+	return s.Known() && p.index == s.Start.index && !p.Before(s.Start) && !p.After(s.End)
+}
+
+// Union returns the smallest span containing both s and t. If one of the
+// spans is unknown, Union returns the other one. Spans from different source
+// files cannot be joined, in which case Union returns s.
+func (s Span) Union(t Span) Span {
+	switch {
+	case !s.Known():
+		return t
+	case !t.Known() || t.Start.index != s.Start.index:
+		return s
+	}
+	if t.Start.Before(s.Start) {
+		s.Start = t.Start
+	}
+	if t.End.After(s.End) {
+		s.End = t.End
+	}
+	return s
+}
+
+// String returns a string representation of s, such as "file:1:5-2:3". If s
+// lies on a single line, the line number is not repeated, as in "file:1:5-9".
+// If s has no associated source file, String returns "<unknown position>".
+func (s Span) String() string {
+	if !s.Known() {
+		return "<unknown position>"
+	}
+	if s.Start.Line() == 0 || s.End.Line() == 0 {
+		return s.Start.String() // file
+	}
+	if s.Start.Line() == s.End.Line() {
+		return fmt.Sprintf("%s-%d", s.Start, s.End.Col()) // file:line:col-col
+	}
+	return fmt.Sprintf("%s-%d:%d", s.Start, s.End.Line(), s.End.Col()) // file:line:col-line:col
+}
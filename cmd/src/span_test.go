@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package src
+
+import "testing"
+
+func TestSpanFormat(t *testing.T) {
+	for _, test := range []struct {
+		span Span
+		want string
+	}{
+		{MakeSpan(MakePos("a.co", 3, 5), MakePos("a.co", 3, 9)), "a.co:3:5-9"},
+		{MakeSpan(MakePos("a.co", 1, 5), MakePos("a.co", 2, 3)), "a.co:1:5-2:3"},
+		{MakeSpan(MakePos("a.co", 0, 0), MakePos("a.co", 0, 0)), "a.co"},
+		{Span{}, "<unknown position>"},
+	} {
+		if got := test.span.String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestSpanContains(t *testing.T) {
+	s := MakeSpan(MakePos("a.co", 1, 5), MakePos("a.co", 2, 3))
+	for _, test := range []struct {
+		pos  Pos
+		want bool
+	}{
+		{MakePos("a.co", 1, 5), true},
+		{MakePos("a.co", 1, 80), true},
+		{MakePos("a.co", 2, 3), true},
+		{MakePos("a.co", 1, 4), false},
+		{MakePos("a.co", 2, 4), false},
+		{MakePos("b.co", 1, 6), false},
+		{NoPos, false},
+	} {
+		if got := s.Contains(test.pos); got != test.want {
+			t.Errorf("%s contains %s: got %v, want %v", s, test.pos, got, test.want)
+		}
+	}
+}
+
+func TestSpanUnion(t *testing.T) {
+	a := MakeSpan(MakePos("a.co", 1, 5), MakePos("a.co", 1, 9))
+	b := MakeSpan(MakePos("a.co", 2, 1), MakePos("a.co", 3, 2))
+	for _, test := range []struct {
+		s, t Span
+		want string
+	}{
+		{a, b, "a.co:1:5-3:2"},
+		{b, a, "a.co:1:5-3:2"},
+		{a, Span{}, "a.co:1:5-9"},
+		{Span{}, b, "a.co:2:1-3:2"},
+		{a, MakeSpan(MakePos("b.co", 1, 1), MakePos("b.co", 9, 9)), "a.co:1:5-9"},
+	} {
+		if got := test.s.Union(test.t).String(); got != test.want {
+			t.Errorf("%s union %s: got %s, want %s", test.s, test.t, got, test.want)
+		}
+	}
+}
@@ -8,8 +8,10 @@ package syntax
 import (
 	"cobalt/base"
 	"cobalt/src"
+	"errors"
 	"io"
 	"os"
+	"slices"
 )
 
 // Error describes a syntax error that occurred at any point while scanning or
@@ -33,34 +35,109 @@ func (e Error) Err() error {
 	return nil
 }
 
+// SortErrors sorts list in source order, as defined by [src.Compare].
+// Errors at the same position keep their relative order, and consecutive
+// duplicates, with the same position and message, are removed. SortErrors
+// sorts list in place and returns the remaining errors.
+func SortErrors(list []Error) []Error {
+	slices.SortStableFunc(list, func(a, b Error) int {
+		return src.Compare(a.Pos, b.Pos)
+	})
+	return slices.Compact(list)
+}
+
+// A Config configures the parsing of source files. The zero Config is ready to
+// use, and is the one used by [Parse], [ParseFile] and [ParseFiles].
+type Config struct {
+	// TabWidth is the number of columns between tab stops, used for computing
+	// the column numbers of source positions. A tab advances the column to the
+	// next tab stop, and any other character by its width in bytes. Values
+	// below 2 count a tab like any other byte; editors commonly use 4 or 8.
+	TabWidth int
+}
+
+func (c *Config) tabwidth() uint {
+	if c.TabWidth < 2 {
+		return 1
+	}
+	return uint(c.TabWidth)
+}
+
 // Parse parses the source code read from an io.Reader and the providded file
 // name. If an error occurs during parsing, a nil [File] and a non-nil error is
 // returned. This is to limit the chances of being able to type-check a
 // malformed syntax tree.
 //
+// Some errors, such as invalid characters, which are skipped, do not prevent
+// parsing the rest of the file. If there are only such errors, the File is
+// returned along with the error. Multiple errors are joined, in source order.
+//
 // Parse panics if a nil io.Reader is provided.
 func Parse(rd io.Reader, name string) (file *File, err error) {
+	return new(Config).Parse(rd, name)
+}
+
+// Parse is like [Parse], but parses with the configuration of c.
+func (c *Config) Parse(rd io.Reader, name string) (file *File, err error) {
 	if rd == nil {
 		panic("syntax: nil io.Reader provided")
 	}
 
+	var p parser
 	defer base.CatchBailout(func(payload any) {
-		file, err = nil, payload.(error)
+		file, err = nil, p.err(payload.(error))
 	})
 
-	var p parser
 	p.init(rd, name)
-	return p.file(), nil
+	p.tabwidth = c.tabwidth()
+	defer p.release() // return the source buffer to the pool
+	file = p.file()
+	return file, p.err(nil)
 }
 
 // ParseFile is a wrapper for [Parse], using only a file name for parsing, it
 // uses the OS's file system to get a reader to parse from.
 func ParseFile(name string) (*File, error) {
+	return new(Config).ParseFile(name)
+}
+
+// ParseFile is like [ParseFile], but parses with the configuration of c.
+func (c *Config) ParseFile(name string) (*File, error) {
 	file, err := os.Open(name)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	return Parse(file, name)
+	return c.Parse(file, name)
+}
+
+// ParseFiles parses the files at paths, in order of their path names, such as
+// for the source files of a module. Contrary to [Parse], it does not stop at
+// the first erroneous file: the errors of all files are joined, and the files
+// which were parsed successfully are returned, still in order.
+func ParseFiles(paths []string) ([]*File, error) {
+	return new(Config).ParseFiles(paths)
+}
+
+// ParseFiles is like [ParseFiles], but parses with the configuration of c.
+func (c *Config) ParseFiles(paths []string) ([]*File, error) {
+	paths = slices.Clone(paths)
+	slices.Sort(paths)
+
+	var files []*File
+	var errs []error
+	for _, path := range paths {
+		file, err := c.ParseFile(path)
+		if list, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = append(errs, list.Unwrap()...)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, errors.Join(errs...)
 }
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"cobalt/src"
+	"slices"
+	"testing"
+)
+
+func TestSortErrors(t *testing.T) {
+	pos := func(file string, line, col uint) src.Pos { return src.MakePos(file, line, col) }
+	list := []Error{
+		{pos("b.co", 1, 1), "e"},
+		{pos("a.co", 2, 1), "d"},
+		{pos("a.co", 1, 5), "b"},
+		{pos("a.co", 1, 5), "c"},
+		{pos("a.co", 1, 5), "b"},
+		{pos("a.co", 1, 2), "a"},
+		{pos("a.co", 2, 1), "d"},
+	}
+	var got []string
+	for _, err := range SortErrors(list) {
+		got = append(got, err.Error())
+	}
+	// errors at the same position keep their order, and only consecutive
+	// duplicates are removed
+	want := []string{"a.co:1:2: a", "a.co:1:5: b", "a.co:1:5: c", "a.co:1:5: b", "a.co:2:1: d", "b.co:1:1: e"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	if got := SortErrors(nil); len(got) != 0 {
+		t.Errorf("got %d errors from none", len(got))
+	}
+}
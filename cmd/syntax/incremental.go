@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements incremental parsing, for use in interactive tools.
+
+package syntax
+
+import (
+	"bytes"
+	"cobalt/base"
+	"errors"
+	"io"
+)
+
+// ErrIncomplete is returned by [Incremental.Next] if the input ends before the
+// declaration or statement being parsed does.
+var ErrIncomplete = errors.New("incomplete input")
+
+// An Incremental parses declarations and statements one at a time, from input
+// which is fed to it piecewise, such as the lines entered in an interactive
+// session. Positions continue across inputs, as if all input had been read
+// from a single file.
+type Incremental struct {
+	p         parser
+	name      string
+	buf       []byte // input not parsed yet
+	line, col uint   // position of buf[0] (0-based)
+}
+
+// NewIncremental returns a new Incremental, which reports positions in the
+// file with the given name.
+func NewIncremental(name string) *Incremental {
+	return new(Config).NewIncremental(name)
+}
+
+// NewIncremental is like [NewIncremental], but parses with the configuration
+// of c.
+func (c *Config) NewIncremental(name string) *Incremental {
+	in := &Incremental{name: name}
+	in.p.names = make(map[string]string)
+	in.p.tabwidth = c.tabwidth()
+	return in
+}
+
+// Feed appends b to the input.
+func (in *Incremental) Feed(b []byte) {
+	in.buf = append(in.buf, b...)
+}
+
+// Next parses the next declaration or statement of the input, where
+// declarations are returned as a *DeclStmt. If the input holds no more than
+// white space, comments and empty statements, Next returns io.EOF.
+//
+// If the input ends before the statement does, Next returns ErrIncomplete,
+// and the statement is parsed again once more input is fed. If the input has
+// a syntax error, Next returns the error and discards all pending input, such
+// that parsing may resume with the input fed afterwards. This includes errors
+// which [Parse] recovers from, such as invalid characters.
+func (in *Incremental) Next() (s Stmt, err error) {
+	p := &in.p
+	defer base.CatchBailout(func(payload any) {
+		s, err = nil, payload.(error)
+		if p.tok == _EOF {
+			err = ErrIncomplete
+			return
+		}
+		err = p.err(err)
+		in.skip(uint(len(in.buf)))
+	})
+
+	// only the source is reset, identifiers remain interned across inputs
+	p.source.init(bytes.NewReader(in.buf), in.name)
+	p.source.line, p.source.col = in.line, in.col
+	p.directives = nil
+	p.errs = nil
+	defer p.release()
+
+	p.next()
+	for p.tok == _Semi {
+		p.next()
+	}
+	if p.tok == _EOF {
+		in.skip(uint(len(in.buf)))
+		if len(p.errs) > 0 {
+			return nil, p.err(nil)
+		}
+		return nil, io.EOF
+	}
+
+	s = p.stmt()
+	if len(p.errs) > 0 {
+		in.skip(uint(len(in.buf)))
+		return nil, p.err(nil)
+	}
+	if p.tok == _EOF {
+		in.skip(uint(len(in.buf)))
+	} else {
+		// keep the input from the start of the lookahead token
+		in.skip(in.offset(p.line-linebase, p.col-colbase))
+	}
+	return s, nil
+}
+
+// offset returns the offset in the pending input of the 0-based position
+// (line, col).
+func (in *Incremental) offset(line, col uint) uint {
+	var n uint
+	for l, c := in.line, in.col; l != line || c != col; n++ {
+		if in.buf[n] == '\n' {
+			l++
+			c = 0
+		} else {
+			c = nextCol(c, rune(in.buf[n]), 1, in.p.tabwidth)
+		}
+	}
+	return n
+}
+
+// skip discards the first n bytes of the pending input.
+func (in *Incremental) skip(n uint) {
+	for _, b := range in.buf[:n] {
+		if b == '\n' {
+			in.line++
+			in.col = 0
+		} else {
+			in.col = nextCol(in.col, rune(b), 1, in.p.tabwidth)
+		}
+	}
+	in.buf = append(in.buf[:0], in.buf[n:]...)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestIncremental(t *testing.T) {
+	in := NewIncremental("repl")
+	next := func(want string) {
+		t.Helper()
+		s, err := in.Next()
+		got := ""
+		if err != nil {
+			got = "error: " + err.Error()
+		} else if _, ok := s.(*DeclStmt); ok {
+			got = s.Pos().String() + " decl " + Sexpr(s)
+		} else {
+			got = s.Pos().String() + " " + Sexpr(s)
+		}
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+
+	// two statements fed sequentially
+	in.Feed([]byte("var x: int32 = 1;\n"))
+	next("repl:1:1 decl (var (name x) (name int32) (lit 1))")
+	in.Feed([]byte("x = x + 1;\n"))
+	next("repl:2:1 (= (name x) (+ (name x) (lit 1)))")
+	if _, err := in.Next(); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+
+	// a statement spanning several inputs
+	in.Feed([]byte("const y =\n"))
+	if _, err := in.Next(); !errors.Is(err, ErrIncomplete) {
+		t.Errorf("got %v, want ErrIncomplete", err)
+	}
+	in.Feed([]byte("  2;\n"))
+	next("repl:3:1 decl (const (name y) nil (lit 2))")
+
+	// the parser is reusable after an error
+	in.Feed([]byte("x = ;\n"))
+	next("error: repl:5:5: expected an expression")
+	in.Feed([]byte("x++;\n"))
+	next("repl:6:2 (++ (name x))")
+}
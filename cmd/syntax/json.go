@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the JSON encoding of syntax trees.
+
+package syntax
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// MarshalJSON returns the JSON encoding of the syntax tree rooted at n, meant
+// for tools such as editor integrations. Each node is encoded as an object
+// holding its concrete type under "Node" and its position under "Pos",
+// followed by its exported fields in declaration order. Positions are encoded
+// as strings, such as "file:1:5", and operators and literal kinds by name.
+// Absent nodes are encoded as null.
+//
+// The encoding is deterministic, but it is not meant to be decoded into a
+// syntax tree again.
+func MarshalJSON(n Node) ([]byte, error) {
+	var e jsonEncoder
+	if err := e.value(reflect.ValueOf(&n).Elem()); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+type jsonEncoder struct{ bytes.Buffer }
+
+func (e *jsonEncoder) value(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		if n, ok := v.Interface().(Node); ok {
+			return e.node(n)
+		}
+		return e.value(v.Elem())
+
+	case reflect.Slice:
+		e.WriteByte('[')
+		for i := range v.Len() {
+			if i > 0 {
+				e.WriteByte(',')
+			}
+			if err := e.value(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		e.WriteByte(']')
+		return nil
+
+	case reflect.Struct:
+		if _, ok := v.Interface().(interface{ MarshalText() ([]byte, error) }); !ok {
+			e.WriteByte('{')
+			err := e.fields(v, false)
+			e.WriteByte('}')
+			return err
+		}
+	}
+
+	// scalars, which includes positions
+	x := v.Interface()
+	switch op := x.(type) {
+	case Operator:
+		if op == 0 {
+			// a simple assignment
+			e.WriteString("null")
+			return nil
+		}
+		x = op.String()
+	case Literal:
+		x = op.String()
+	}
+	b, err := json.Marshal(x)
+	e.Write(b)
+	return err
+}
+
+// node encodes the node n.
+func (e *jsonEncoder) node(n Node) error {
+	v := reflect.ValueOf(n).Elem()
+	e.WriteString(`{"Node":`)
+	e.value(reflect.ValueOf(v.Type().Name()))
+	e.WriteString(`,"Pos":`)
+	e.value(reflect.ValueOf(n.Pos()))
+	err := e.fields(v, true)
+	e.WriteByte('}')
+	return err
+}
+
+// fields encodes the exported fields of the struct v as members of an object.
+// If more is set, the members follow others.
+func (e *jsonEncoder) fields(v reflect.Value, more bool) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue // position of nodes
+		}
+		if more {
+			e.WriteByte(',')
+		}
+		more = true
+		e.value(reflect.ValueOf(f.Name))
+		e.WriteByte(':')
+		if err := e.value(v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
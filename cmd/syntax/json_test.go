@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestMarshalJSON(t *testing.T) {
+	const src = "const f = proc(x: *const int32) ?int32 { return x.* + 1; };"
+	f, err := Parse(strings.NewReader(src), "test.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalJSON(f.DeclList[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := json.Indent(&got, data, "", "\t"); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	got.WriteByte('\n')
+
+	const golden = "testdata/decl.json"
+	if *update {
+		if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("got\n%s\nwant\n%s", got.Bytes(), want)
+	}
+
+	// the encoding is deterministic
+	if again, _ := MarshalJSON(f.DeclList[0]); !bytes.Equal(again, data) {
+		t.Errorf("encoding differs between runs")
+	}
+}
@@ -15,22 +15,55 @@ type Node interface {
 	// children.
 	Pos() src.Pos
 
+	// SetPos sets the position of the Node. It is meant for synthesized
+	// nodes, see Synthesized.
+	SetPos(src.Pos)
+
 	sNode() // prohibits external implementations
 }
 
 type node struct{ pos src.Pos }
 
-func (n *node) Pos() src.Pos { return n.pos }
-func (*node) sNode()         {}
+func (n *node) Pos() src.Pos       { return n.pos }
+func (n *node) SetPos(pos src.Pos) { n.pos = pos }
+func (*node) sNode()               {}
+
+// Synthesized positions the node n, which is not the result of parsing but
+// synthesized by a later pass, and returns n. By convention, a synthesized node
+// has the position of the node it replaces, such that diagnostics concerning it
+// still point to the original source. For example, a literal resulting from
+// constant folding has the position of the folded operation:
+//
+//	lit := syntax.Synthesized(op.Pos(), &syntax.LiteralExpr{Value: "3", Kind: syntax.Int})
+func Synthesized[N Node](pos src.Pos, n N) N {
+	n.SetPos(pos)
+	return n
+}
 
 // ----------------------------------------------------------------------------
 // Files
 
 // File is a node representing the entirety of a source file.
 type File struct {
-	DeclList []Decl
-	EOF      src.Pos
-	node     // position of first non-comment token in file
+	DeclList   []Decl
+	Directives []Directive // in source order
+	EOF        src.Pos
+	Stats      Stats
+	node       // position of first non-comment token in file, or EOF if none
+}
+
+// Stats holds statistics about a source file, as gathered by the scanner.
+type Stats struct {
+	Lines  uint // number of lines, including a last line without newline
+	Tokens uint // number of tokens, excluding comments and EOF
+	Bytes  uint // size in bytes
+}
+
+// Directive is a line comment of the form "// cobalt:text", which instructs
+// the compiler rather than the reader, such as "// cobalt:ignore unused".
+type Directive struct {
+	Pos  src.Pos // position of "//"
+	Text string  // text following "cobalt:"
 }
 
 // ----------------------------------------------------------------------------
@@ -59,6 +92,27 @@ type (
 		Values   Expr // nil means no init expression
 		decl          // position of "var"
 	}
+
+	// TypeDecl is a type declaration.
+	TypeDecl struct {
+		Name *Name
+		Type Expr
+		decl // position of "type"
+	}
+
+	// EnumDecl is an enum declaration.
+	EnumDecl struct {
+		Name *Name
+		List []*Enumerator
+		decl // position of "enum"
+	}
+
+	// Enumerator is a named value in an enum declaration.
+	Enumerator struct {
+		Name  *Name
+		Value Expr // nil means the previous value plus one
+		node       // position of Name field
+	}
 )
 
 // decl ensures that all declaration nodes implement both Node and Decl.
@@ -89,6 +143,11 @@ type (
 		expr  // position of literal
 	}
 
+	// NoneExpr is the literal none, the absent value of an option type.
+	NoneExpr struct {
+		expr // position of "none"
+	}
+
 	// CompoundExpr is a compound expression for a compound type.
 	CompoundExpr struct {
 		List []Expr
@@ -123,18 +182,28 @@ type (
 		expr // position of "?"
 	}
 
-	// CallExpr is a call to a procedure.
+	// CallExpr is a call to a procedure. If Proc is parenthesized and denotes
+	// a type, as in (int32)(x), the call is a cast instead, which is only known
+	// once names are resolved.
 	CallExpr struct {
 		Proc    Expr
 		ArgList []Expr
-		expr    // position of "("
+		Paren   bool // Proc is parenthesized
+		expr         // position of "("
+	}
+
+	// SelectorExpr is a selection of a struct field.
+	SelectorExpr struct {
+		X    Expr
+		Sel  *Name
+		expr // position of "."
 	}
 
-	// CastExpr is a type cast expression.
+	// CastExpr is a type cast expression, (Type)X or X as Type.
 	CastExpr struct {
 		Type Expr
 		X    Expr
-		expr // position of "("
+		expr // position of "(" or "as"
 	}
 
 	// IndexExpr is an array index expression.
@@ -163,7 +232,8 @@ type (
 		expr // position of "?"
 	}
 
-	// ArrayType is a fixed-length array type.
+	// ArrayType is a fixed-length array type. Len is the blank name _ if the
+	// length is inferred from a compound literal, as in [_]int32{1, 2, 3}.
 	ArrayType struct {
 		Len  Expr
 		Elem Expr
@@ -232,11 +302,38 @@ type (
 		stmt     // position of Op field
 	}
 
+	// IncDecStmt is an increment or decrement statement, x++ or x--. The
+	// prefix forms ++x and --x are equivalent.
+	IncDecStmt struct {
+		Op   Operator // Inc or Dec
+		X    Expr
+		stmt // position of Op field
+	}
+
 	// ReturnStmt is a procedure return statement.
 	ReturnStmt struct {
 		Result Expr
 		stmt   // position of "return"
 	}
+
+	// LabeledStmt is a statement preceded by a label.
+	LabeledStmt struct {
+		Label *Name
+		Stmt  Stmt
+		stmt  // position of Label field
+	}
+
+	// DeferStmt is a call deferred until the procedure returns.
+	DeferStmt struct {
+		Call *CallExpr
+		stmt // position of "defer"
+	}
+
+	// GotoStmt is a jump to a labeled statement.
+	GotoStmt struct {
+		Label *Name
+		stmt  // position of "goto"
+	}
 )
 
 type stmt struct{ node }
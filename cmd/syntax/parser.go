@@ -9,7 +9,7 @@ import (
 	"cobalt/src"
 )
 
-const trace = debug.Enabled && false // for if we want parser tracing
+const trace = false // for if we want parser tracing, subject to debug.Enabled
 
 type parser struct{ scanner }
 
@@ -51,6 +51,9 @@ func (p *parser) error(msg string) {
 // ----------------------------------------------------------------------------
 // Source file(s)
 
+// file parses a complete source file. A file without declarations, such as an
+// empty file or one containing only comments, is valid. Its position is then
+// that of EOF, which is known like any other.
 func (p *parser) file() *File {
 	if trace {
 		defer debug.Trace()()
@@ -67,6 +70,8 @@ func (p *parser) file() *File {
 
 	// p.tok == _EOF
 	f.EOF = p.pos()
+	f.Directives = p.directives
+	f.Stats = p.stats()
 	return f
 }
 
@@ -84,6 +89,12 @@ func (p *parser) decl(global bool) Decl {
 
 	case _Var:
 		return p.varDecl()
+
+	case _Type:
+		return p.typeDecl()
+
+	case _Enum:
+		return p.enumDecl()
 	}
 
 	p.error("expected a declaration")
@@ -129,6 +140,50 @@ func (p *parser) varDecl() *VarDecl {
 	return d
 }
 
+func (p *parser) typeDecl() *TypeDecl {
+	if trace {
+		defer debug.Trace()()
+	}
+
+	d := new(TypeDecl)
+	d.pos = p.want(_Type)
+
+	d.Name = p.name()
+	d.Type = p.type_()
+
+	p.semi()
+	return d
+}
+
+func (p *parser) enumDecl() *EnumDecl {
+	if trace {
+		defer debug.Trace()()
+	}
+
+	d := new(EnumDecl)
+	d.pos = p.want(_Enum)
+	d.Name = p.name()
+
+	// like blocks, enums are not followed by a semicolon
+	p.want(_Lbrace)
+	for p.tok != _EOF && p.tok != _Rbrace {
+		e := new(Enumerator)
+		e.pos = p.pos()
+		e.Name = p.name()
+		if p.got(_Assign) {
+			e.Value = p.expr()
+		}
+		d.List = append(d.List, e)
+
+		if !p.got(_Comma) && p.tok != _Rbrace {
+			p.error("expected a comma or \"}\"")
+		}
+	}
+	p.want(_Rbrace)
+
+	return d
+}
+
 func (p *parser) initialization(tok token) Expr {
 	if trace {
 		defer debug.Trace()()
@@ -176,7 +231,7 @@ func (p *parser) stmt() Stmt {
 	}
 
 	switch p.tok {
-	case _Const, _Var:
+	case _Const, _Enum, _Type, _Var:
 		return p.declStmt()
 
 	case _Lbrace:
@@ -185,6 +240,12 @@ func (p *parser) stmt() Stmt {
 	case _Return:
 		return p.returnStmt()
 
+	case _Goto:
+		return p.gotoStmt()
+
+	case _Defer:
+		return p.deferStmt()
+
 	default:
 		return p.simpleStmt()
 	}
@@ -197,6 +258,16 @@ func (p *parser) simpleStmt() Stmt {
 
 	lhs := p.exprList()
 
+	// a lone name followed by a colon labels the following statement; type
+	// annotations only follow names in declarations, so this is unambiguous
+	if name, ok := lhs.(*Name); ok && p.got(_Colon) {
+		s := new(LabeledStmt)
+		s.pos = name.pos
+		s.Label = name
+		s.Stmt = p.stmt()
+		return s
+	}
+
 	if _, ok := lhs.(*ListExpr); ok {
 		if p.got(_Assign) {
 			return p.assign(lhs, 0, p.exprList())
@@ -221,6 +292,18 @@ func (p *parser) simpleStmt() Stmt {
 		// expression statement so p.tok should be semicolon
 		p.semi()
 
+		// a lone increment or decrement is a statement of its own
+		if op, ok := lhs.(*Operation); ok && (op.Op == Inc || op.Op == Dec) {
+			s := new(IncDecStmt)
+			s.pos = op.pos
+			s.Op = op.Op
+			s.X = op.Lhs // x++, x--
+			if s.X == nil {
+				s.X = op.Rhs // ++x, --x
+			}
+			return s
+		}
+
 		s := new(ExprStmt)
 		s.pos = lhs.Pos()
 		s.X = lhs
@@ -262,7 +345,7 @@ func (p *parser) blockStmt() *BlockStmt {
 	for p.tok != _EOF && p.tok != _Rbrace {
 		s.StmtList = append(s.StmtList, p.stmt())
 	}
-	p.want(_Rbrace)
+	s.Closing = p.want(_Rbrace)
 
 	// a semicolon is not required after a block statement
 	return s
@@ -284,6 +367,38 @@ func (p *parser) returnStmt() *ReturnStmt {
 	return s
 }
 
+func (p *parser) deferStmt() *DeferStmt {
+	if trace {
+		defer debug.Trace()()
+	}
+
+	s := new(DeferStmt)
+	s.pos = p.want(_Defer)
+
+	x := p.expr()
+	call, ok := x.(*CallExpr)
+	if !ok {
+		p.errorAt(x.Pos(), "expected function call in defer")
+	}
+	s.Call = call
+
+	p.semi()
+	return s
+}
+
+func (p *parser) gotoStmt() *GotoStmt {
+	if trace {
+		defer debug.Trace()()
+	}
+
+	s := new(GotoStmt)
+	s.pos = p.want(_Goto)
+	s.Label = p.name()
+
+	p.semi()
+	return s
+}
+
 // ----------------------------------------------------------------------------
 // Expressions
 
@@ -337,18 +452,32 @@ func (p *parser) binaryExpr(x Expr, prec int) Expr {
 		x = p.unaryExpr()
 	}
 
-	for (p.tok == _Operator || p.tok == _Star) && p.prec > prec {
-		t := new(Operation)
-		t.pos = p.pos()
-		t.Op = p.op
-		tprec := p.prec
-		p.next()
-		t.Lhs = x
-		t.Rhs = p.binaryExpr(nil, tprec)
-		x = t
-	}
+	for {
+		switch {
+		case (p.tok == _Operator || p.tok == _Star) && p.prec > prec:
+			t := new(Operation)
+			t.pos = p.pos()
+			t.Op = p.op
+			tprec := p.prec
+			p.next()
+			t.Lhs = x
+			t.Rhs = p.binaryExpr(nil, tprec)
+			x = t
 
-	return x
+		case p.tok == _As && precAs > prec:
+			// x as T is an alternative to the cast (T)x, which binds less
+			// tightly than comparisons
+			t := new(CastExpr)
+			t.pos = p.pos()
+			p.next()
+			t.Type = p.type_()
+			t.X = x
+			x = t
+
+		default:
+			return x
+		}
+	}
 }
 
 func (p *parser) unaryExpr() Expr {
@@ -402,11 +531,14 @@ func (p *parser) postfixUnary(x Expr) Expr {
 
 			t.Lhs = x
 			x = t
+
+		default:
+			// not a postfix operator, so this could be the lhs of a
+			// binary expression.
+			return x
 		}
 	}
 
-	// no default case, as this could be the lhs of a binary expression.
-
 	return x
 }
 
@@ -424,6 +556,9 @@ func (p *parser) primaryExpr() Expr {
 		case _Lbrack:
 			x = p.indexExpr(x)
 
+		case _Dot:
+			x = p.selectorExpr(x)
+
 		default:
 			return x
 		}
@@ -460,6 +595,16 @@ func (p *parser) atomExprOrNil() Expr {
 		x := p.expr()
 		p.want(_Rparen)
 
+		// (T)x is a cast and (f)(x) is a call. Whether a parenthesized name
+		// followed by "(" denotes a type is only known to the checker, so
+		// (T)(x) is parsed as a call with a parenthesized callee, unless T is
+		// syntactically a type literal, as in (*int32)(p).
+		if p.tok == _Lparen && !isTypeLit(x) {
+			call := p.callExpr(x)
+			call.Paren = true
+			return call
+		}
+
 		if t := p.atomExprOrNil(); t != nil {
 			c := new(CastExpr)
 			c.pos = pos
@@ -469,6 +614,12 @@ func (p *parser) atomExprOrNil() Expr {
 
 		return x
 
+	case _None:
+		x := new(NoneExpr)
+		x.pos = p.pos()
+		p.next()
+		return x
+
 	case _Lbrace:
 		return p.compoundExpr()
 
@@ -488,6 +639,16 @@ func (p *parser) atomExprOrNil() Expr {
 	}
 }
 
+// isTypeLit reports whether x is a type literal, i.e. an expression which
+// can only denote a type.
+func isTypeLit(x Expr) bool {
+	switch x.(type) {
+	case *PointerType, *OptionType, *ArrayType, *ProcType, *StructType:
+		return true
+	}
+	return false
+}
+
 func (p *parser) compoundExpr() *CompoundExpr {
 	if trace {
 		defer debug.Trace()()
@@ -543,13 +704,14 @@ func (p *parser) callExpr(x Expr) *CallExpr {
 	t.Proc = x
 
 	p.want(_Lparen)
-	if p.got(_Rparen) {
-		return t
-	}
 
-	list := []Expr{p.expr()}
-	for p.got(_Comma) {
+	// a trailing comma is permitted, as in f(a, b,)
+	var list []Expr
+	for p.tok != _EOF && p.tok != _Rparen {
 		list = append(list, p.expr())
+		if !p.got(_Comma) && p.tok != _Rparen {
+			p.error("expected comma or \")\"")
+		}
 	}
 	p.want(_Rparen)
 
@@ -557,6 +719,18 @@ func (p *parser) callExpr(x Expr) *CallExpr {
 	return t
 }
 
+func (p *parser) selectorExpr(x Expr) *SelectorExpr {
+	if trace {
+		defer debug.Trace()()
+	}
+
+	t := new(SelectorExpr)
+	t.pos = p.want(_Dot)
+	t.X = x
+	t.Sel = p.name()
+	return t
+}
+
 func (p *parser) indexExpr(x Expr) *IndexExpr {
 	if trace {
 		defer debug.Trace()()
@@ -616,6 +790,13 @@ func (p *parser) typeOrNil() Expr {
 	case _Name:
 		return p.name()
 
+	case _Type:
+		// the type of types is named by the keyword
+		n := new(Name)
+		n.Value, n.pos = "type", p.pos()
+		p.next()
+		return n
+
 	case _Star:
 		x := new(PointerType)
 		x.pos = p.pos()
@@ -645,6 +826,14 @@ func (p *parser) typeOrNil() Expr {
 
 	case _Struct:
 		return p.structType()
+
+	case _Lparen:
+		// parentheses group a type, as in *(proc() int32). Expressions
+		// handle "(" themselves, so this does not interfere with casts.
+		p.next()
+		x := p.type_()
+		p.want(_Rparen)
+		return x
 	}
 
 	return nil
@@ -659,6 +848,8 @@ func (p *parser) procType() *ProcType {
 	typ.pos = p.want(_Proc)
 
 	typ.ParamList = p.paramList()
+	// the result may itself be a procedure type, as in proc() proc(int32) int32,
+	// where the innermost procedure type takes the last result type
 	typ.Result = p.typeOrNil()
 
 	return typ
@@ -669,30 +860,29 @@ func (p *parser) paramList() []*Field {
 		defer debug.Trace()()
 	}
 
-	pos := p.want(_Lparen)
+	p.want(_Lparen)
 	if p.got(_Rparen) {
 		return nil
 	}
 
+	// either all parameters are named or none are, as decided by the first
 	var list []*Field
-	var named, unnamed bool
+	var named bool
 	for p.tok != _EOF && p.tok != _Rparen {
 		f, isNamed := p.field()
+		if len(list) == 0 {
+			named = isNamed
+		} else if isNamed != named {
+			p.errorAt(f.pos, "cannot mix named and unnamed parameters")
+		}
 		list = append(list, f)
 
-		named = named || isNamed
-		unnamed = unnamed || !isNamed
-
 		if !p.got(_Comma) && p.tok != _Rparen {
 			p.error("expected a comma or \")\"")
 		}
 	}
 	p.want(_Rparen)
 
-	if named && unnamed {
-		p.errorAt(pos, "got mixed named and unnamed parameters")
-	}
-
 	return list
 }
 
@@ -0,0 +1,378 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmptyBlock(t *testing.T) {
+	file, err := Parse(strings.NewReader("const f = proc() {\n};"), "test.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := file.DeclList[0].(*ConstDecl).Values.(*ProcExpr).Body
+	if len(body.StmtList) != 0 {
+		t.Errorf("got %d statements, want none", len(body.StmtList))
+	}
+	if got := body.Closing; got.Line() != 2 || got.Col() != 1 {
+		t.Errorf("closing brace at %d:%d, want 2:1", got.Line(), got.Col())
+	}
+}
+
+func TestBinaryExpr(t *testing.T) {
+	for _, src := range []string{
+		"const x = 1 + 2;",
+		"const x = a * b - c;",
+		"const x = a++ + b;",
+	} {
+		if err := parseError(src); err != "" {
+			t.Errorf("%q: %s", src, err)
+		}
+	}
+}
+
+func TestSynthesized(t *testing.T) {
+	f, err := Parse(strings.NewReader("const x = 1 + 2;"), "test.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := f.DeclList[0].(*ConstDecl).Values
+	lit := Synthesized(op.Pos(), &LiteralExpr{Value: "3", Kind: Int})
+	if lit.Pos() != op.Pos() {
+		t.Errorf("got position %v, want %v", lit.Pos(), op.Pos())
+	}
+	if got, want := lit.Pos().String(), "test.co:1:13"; got != want {
+		t.Errorf("got position %s, want %s", got, want)
+	}
+}
+
+// sexprOf parses src and returns the S-expression of its first declaration.
+func sexprOf(t *testing.T, src string) string {
+	t.Helper()
+	f, err := Parse(strings.NewReader(src), "test.co")
+	if err != nil {
+		t.Fatalf("%q: %v", src, err)
+	}
+	return Sexpr(f.DeclList[0])
+}
+
+func TestHigherOrderProcType(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"var f: proc() proc(int32) int32;", "(var (name f) (proctype (params) (proctype (params (field nil (name int32))) (name int32))) nil)"},
+		{"var f: proc(g: proc(int32) int32) proc() void;", "(var (name f) (proctype (params (field (name g) (proctype (params (field nil (name int32))) (name int32)))) (proctype (params) (name void))) nil)"},
+		{"var f: proc() proc() proc();", "(var (name f) (proctype (params) (proctype (params) (proctype (params) nil))) nil)"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestMixedParams(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{"var f: proc(int32, x: int32);", "test.co:1:20: cannot mix named and unnamed parameters"},
+		{"var f: proc(x: int32, int32);", "test.co:1:23: cannot mix named and unnamed parameters"},
+		{"var f: proc(a: int32, b: int8, int8);", "test.co:1:32: cannot mix named and unnamed parameters"},
+		{"var f: proc(x, y: int32);", "test.co:1:16: cannot mix named and unnamed parameters"},
+		{"var f: proc(x: int32);", ""},
+		{"var f: proc(int32);", ""},
+		{"var f: proc(x: int32, y: int8);", ""},
+		{"var f: proc(int32, int8);", ""},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got error %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestTypeDecl(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"type Celsius int32;", "(type (name Celsius) (name int32))"},
+		{"type P *const Celsius;", "(type (name P) (ptr const (name Celsius)))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+	if err := parseError("type Celsius;"); err == "" {
+		t.Errorf("type declaration without type: got no error")
+	}
+}
+
+func TestEnumDecl(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"enum Color { Red, Green = 5, Blue }", "(enum (name Color) (enumerator (name Red) nil) (enumerator (name Green) (lit 5)) (enumerator (name Blue) nil))"},
+		{"enum E { A, }", "(enum (name E) (enumerator (name A) nil))"},
+		{"enum E {}", "(enum (name E))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestLabels(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"const f = proc() { loop: x = x + 1; goto loop; };", "(const (name f) nil (proc (proctype (params) nil) (block (label (name loop) (= (name x) (+ (name x) (lit 1)))) (goto (name loop)))))"},
+		{"const f = proc() { a: b: goto a; };", "(const (name f) nil (proc (proctype (params) nil) (block (label (name a) (label (name b) (goto (name a)))))))"},
+		// a colon following a lone name in statement context introduces a label
+		{"const f = proc() { x: int32; };", "(const (name f) nil (proc (proctype (params) nil) (block (label (name x) (expr (name int32))))))"},
+		{"const f = proc() { var x: int32; };", "(const (name f) nil (proc (proctype (params) nil) (block (var (name x) (name int32) nil))))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestDeferStmt(t *testing.T) {
+	const want = "(const (name f) nil (proc (proctype (params) nil) (block (defer (call (name g) (lit 1))))))"
+	if got := sexprOf(t, "const f = proc() { defer g(1); };"); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	for _, test := range []struct{ src, err string }{
+		{"const f = proc() { defer g; };", "test.co:1:26: expected function call in defer"},
+		{"const f = proc() { defer g() + 1; };", "test.co:1:30: expected function call in defer"},
+		{"const f = proc() { defer g() };", "test.co:1:30: expected semicolon"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got error %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestCastOrCall(t *testing.T) {
+	for _, test := range []struct {
+		src, want string
+		paren     bool
+	}{
+		{"const y = (int32)x;", "(cast (name int32) (name x))", false},
+		{"const y = (*int32)(x);", "(cast (ptr (name int32)) (name x))", false},
+		// (T)(x) and (f)(x) are only told apart by the checker
+		{"const y = (int32)(x);", "(call (name int32) (name x))", true},
+		{"const y = (f)(x);", "(call (name f) (name x))", true},
+		{"const y = (a + b)(c);", "(call (+ (name a) (name b)) (name c))", true},
+		{"const y = f(x);", "(call (name f) (name x))", false},
+		{"const y = (f)(x)(z);", "(call (call (name f) (name x)) (name z))", false},
+	} {
+		f, err := Parse(strings.NewReader(test.src), "test.co")
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		x := f.DeclList[0].(*ConstDecl).Values
+		if got := Sexpr(x); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+		if call, ok := x.(*CallExpr); ok && call.Paren != test.paren {
+			t.Errorf("%q: got Paren = %v, want %v", test.src, call.Paren, test.paren)
+		}
+	}
+}
+
+func TestNoneLiteral(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"var x: ?int32 = none;", "(var (name x) (option (name int32)) none)"},
+		{"const f = proc() { x = none; };", "(const (name f) nil (proc (proctype (params) nil) (block (= (name x) none))))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestParenthesizedType(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"var x: (*int32);", "(var (name x) (ptr (name int32)) nil)"},
+		{"var x: (proc() int32);", "(var (name x) (proctype (params) (name int32)) nil)"},
+		{"var x: *(proc() int32);", "(var (name x) (ptr (proctype (params) (name int32))) nil)"},
+		{"var x: ?(*int32);", "(var (name x) (option (ptr (name int32))) nil)"},
+		// in an expression, a parenthesized type is a cast
+		{"const x = (*int32)(p);", "(const (name x) nil (cast (ptr (name int32)) (name p)))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestEmptyFile(t *testing.T) {
+	for _, test := range []struct{ src, pos, eof string }{
+		{"", "test.co:1:1", "test.co:1:1"},
+		{"  \n\t\n", "test.co:2:2", "test.co:2:2"}, // EOF after a final newline is on the last line
+		{"// a\n/* b */ ", "test.co:2:9", "test.co:2:9"},
+	} {
+		f, err := Parse(strings.NewReader(test.src), "test.co")
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		if len(f.DeclList) != 0 {
+			t.Errorf("%q: got %d declarations, want none", test.src, len(f.DeclList))
+		}
+		if got := f.Pos().String(); got != test.pos {
+			t.Errorf("%q: got position %s, want %s", test.src, got, test.pos)
+		}
+		if got := f.EOF.String(); got != test.eof {
+			t.Errorf("%q: got EOF %s, want %s", test.src, got, test.eof)
+		}
+	}
+}
+
+func TestInvalidAssignOp(t *testing.T) {
+	// there are no compound forms of the logical operators
+	for _, src := range []string{"const f = proc() { b &&= c; };", "const f = proc() { b ||= c; };"} {
+		if got, want := parseError(src), "test.co:1:24: expected an expression"; got != want {
+			t.Errorf("%q: got %q, want %q", src, got, want)
+		}
+	}
+}
+
+func TestIncDecStmt(t *testing.T) {
+	f, err := Parse(strings.NewReader("const f = proc() { x++; --x; x + y; p.*++; };"), "test.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := f.DeclList[0].(*ConstDecl).Values.(*ProcExpr).Body.StmtList
+	if len(body) != 4 {
+		t.Fatalf("got %d statements, want 4", len(body))
+	}
+	for i, op := range map[int]Operator{0: Inc, 1: Dec, 3: Inc} {
+		if s, ok := body[i].(*IncDecStmt); !ok || s.Op != op {
+			t.Errorf("statement %d: got %s, want %s statement", i, Sexpr(body[i]), op)
+		}
+	}
+	if _, ok := body[2].(*ExprStmt); !ok {
+		t.Errorf("statement 2: got %T, want *ExprStmt", body[2])
+	}
+}
+
+func TestStats(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want Stats
+	}{
+		{"", Stats{0, 0, 0}},
+		{"const x = 1;", Stats{1, 5, 12}},
+		{"const x = 1;\n", Stats{1, 5, 13}},
+		{"// a\nconst x = 1;\nvar y: int32 = x + 2; /* b */\n\n", Stats{4, 14, 49}},
+	} {
+		f, err := Parse(strings.NewReader(test.src), "test.co")
+		if err != nil {
+			t.Errorf("%q: %v", test.src, err)
+			continue
+		}
+		if f.Stats != test.want {
+			t.Errorf("%q: got %+v, want %+v", test.src, f.Stats, test.want)
+		}
+	}
+}
+
+func TestSexpr(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"const x = 1 + 2 * y;", "(const (name x) nil (+ (lit 1) (* (lit 2) (name y))))"},
+		{"const f = proc(a: int32, b: bool) int32 { return a; };",
+			"(const (name f) nil (proc (proctype (params (field (name a) (name int32)) (field (name b) (name bool))) (name int32)) (block (return (name a)))))"},
+		{"type P struct{x: int32; y: [4]?*int8;};",
+			"(type (name P) (struct (field (name x) (name int32)) (field (name y) (array (lit 4) (option (ptr (name int8)))))))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+	if got := Sexpr(nil); got != "nil" {
+		t.Errorf("got %s for a nil node, want nil", got)
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	b := write("b.co", "const y = x;")
+	a := write("a.co", "const x = 1;")
+	bad := write("c.co", "const z = ;")
+
+	// files are returned in order of their paths
+	files, err := ParseFiles([]string{b, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Pos().Filename() != a || files[1].Pos().Filename() != b {
+		t.Fatalf("got files in wrong order")
+	}
+
+	// erroneous files are skipped, but do not stop the others
+	files, err = ParseFiles([]string{bad, b, a})
+	if want := bad + ":1:11: expected an expression"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	if len(files) != 2 {
+		t.Errorf("got %d files, want 2", len(files))
+	}
+}
+
+func TestAsExpr(t *testing.T) {
+	// as binds less tightly than comparisons, but more than && and ||
+	for _, test := range []struct{ src, want string }{
+		{"const y = x as int32;", "(const (name y) nil (cast (name int32) (name x)))"},
+		{"const y = a + b as int64;", "(const (name y) nil (cast (name int64) (+ (name a) (name b))))"},
+		{"const y = a < b as int8;", "(const (name y) nil (cast (name int8) (< (name a) (name b))))"},
+		{"const y = a && b as bool;", "(const (name y) nil (&& (name a) (cast (name bool) (name b))))"},
+		{"const y = x as *int32 as uintptr;", "(const (name y) nil (cast (name uintptr) (cast (ptr (name int32)) (name x))))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+	if got, want := parseError("const y = x as;"), "test.co:1:15: expected a type"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestInferredArrayLength(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"const a = ([_]int32){1, 2, 3};", "(const (name a) nil (cast (array (name _) (name int32)) (compound (lit 1) (lit 2) (lit 3))))"},
+		// [_] is rejected by the type checker outside of compound literals
+		{"var a: [_]int32;", "(var (name a) (array (name _) (name int32)) nil)"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+}
+
+func TestTrailingCommas(t *testing.T) {
+	for _, test := range []struct{ src, want string }{
+		{"const x = f(a,);", "(const (name x) nil (call (name f) (name a)))"},
+		{"const x = f(a, b,);", "(const (name x) nil (call (name f) (name a) (name b)))"},
+		{"const x = ([2]int32){1, 2,};", "(const (name x) nil (cast (array (lit 2) (name int32)) (compound (lit 1) (lit 2))))"},
+		{"const x = (P){.x = 1,};", "(const (name x) nil (cast (name P) (compound (assign (name x) (lit 1)))))"},
+	} {
+		if got := sexprOf(t, test.src); got != test.want {
+			t.Errorf("%q:\ngot  %s\nwant %s", test.src, got, test.want)
+		}
+	}
+
+	// a comma must follow an element
+	for _, test := range []struct{ src, err string }{
+		{"const x = f(,);", "test.co:1:13: expected an expression"},
+		{"const x = f(a,,);", "test.co:1:15: expected an expression"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
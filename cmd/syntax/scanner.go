@@ -5,7 +5,10 @@
 package syntax
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -20,6 +23,39 @@ type scanner struct {
 	kind      Literal  // valid if tok is _Literal
 	op        Operator // valid if tok is _Operator, _Star, _AssignOp, or _IncOp
 	prec      int      // valid if tok is _Operator, _Star, _AssignOp, or _IncOp
+
+	names      map[string]string // interned identifiers
+	directives []Directive       // directives read so far
+	tokens     uint              // number of tokens read so far, excluding EOF
+	errs       []error           // errors recovered from so far
+}
+
+func (s *scanner) init(in io.Reader, file string) {
+	s.source.init(in, file)
+	s.names = make(map[string]string)
+}
+
+// stats returns the statistics of the source read so far, which are those of
+// the entire file once EOF is reached.
+func (s *scanner) stats() Stats {
+	lines := s.source.line
+	if s.source.col > 0 {
+		lines++ // last line not terminated by a newline
+	}
+	return Stats{Lines: lines, Tokens: s.tokens, Bytes: s.offs}
+}
+
+// err returns the errors recovered from, followed by last if it is not nil, as
+// a single error. It returns nil if there are no errors.
+func (s *scanner) err(last error) error {
+	errs := s.errs
+	if last != nil {
+		errs = append(errs[:len(errs):len(errs)], last)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
 }
 
 // errorf reports an error at the most recently read character position.
@@ -49,6 +85,9 @@ redo:
 	// token start
 	s.line, s.col = s.pos()
 	s.start()
+	if s.ch >= 0 {
+		s.tokens++
+	}
 	if isLetter(s.ch) || s.ch >= utf8.RuneSelf && unicode.IsLetter(s.ch) {
 		s.nextch()
 		s.name()
@@ -152,6 +191,7 @@ redo:
 		s.nextch()
 		if s.ch == '/' || s.ch == '*' {
 			s.comment()
+			s.tokens-- // comments are not tokens
 			goto redo
 		}
 		s.op, s.prec = Div, precMul
@@ -253,7 +293,12 @@ redo:
 		s.tok = _Cond
 
 	default:
-		s.errorf("invalid character %#U", s.ch)
+		// skip the character, such that a single stray character does not
+		// end the parse
+		s.errs = append(s.errs, Error{s.at(s.line, s.col), fmt.Sprintf("invalid character %#U", s.ch)})
+		s.nextch()
+		s.tokens--
+		goto redo
 	}
 
 	return
@@ -289,16 +334,31 @@ func (s *scanner) name() {
 			s.tok = tok
 			return
 		}
+		if reserved[string(lit)] {
+			s.errorAt(s.at(s.line, s.col), fmt.Sprintf("%s is reserved but not yet implemented", lit))
+		}
 	}
 
 	if len(lit) > maxlength {
 		s.errorAt(s.at(s.line, s.col), "excessively long name")
 	}
 
-	s.lit = string(lit)
+	s.lit = s.intern(lit)
 	s.tok = _Name
 }
 
+// intern returns the string for lit, sharing a single copy among all equal
+// names in the source file. The map lookup does not allocate, so only the
+// first occurrence of each name costs an allocation.
+func (s *scanner) intern(lit []byte) string {
+	if name, ok := s.names[string(lit)]; ok {
+		return name
+	}
+	name := string(lit)
+	s.names[name] = name
+	return name
+}
+
 func (s *scanner) atIdentChar() bool {
 	if unicode.IsLetter(s.ch) || unicode.IsDigit(s.ch) || s.ch == '_' {
 		return true
@@ -311,6 +371,20 @@ func (s *scanner) atIdentChar() bool {
 
 var keywordMap map[string]token
 
+// reserved holds the words reserved for keywords of features which are not
+// implemented yet. Once a feature lands, its words move to the keywords.
+var reserved = map[string]bool{
+	"break":    true,
+	"case":     true,
+	"continue": true,
+	"else":     true,
+	"for":      true,
+	"if":       true,
+	"import":   true,
+	"switch":   true,
+	"while":    true,
+}
+
 func init() {
 	// populate keywordMap
 	keywordMap = make(map[string]token, keywordLast-keywordFirst-1)
@@ -332,8 +406,10 @@ func (s *scanner) digits(base int, invalid *int) (digsep int) {
 			if s.ch == '_' {
 				ds = 2
 			} else if s.ch >= max && *invalid < 0 {
+				// record the index of the invalid rune in the literal, which
+				// starts at s.col and includes any prefix such as "0o"
 				_, col := s.pos()
-				*invalid = int(col - s.col) // record invalid rune index
+				*invalid = int(col - s.col)
 			}
 			digsep |= ds
 			s.nextch()
@@ -381,12 +457,16 @@ func (s *scanner) number(seenPoint bool) {
 		}
 		digsep |= s.digits(base, &invalid)
 		if s.ch == '.' {
-			if prefix != 0 {
+			if prefix != 0 && prefix != '0' {
 				s.error("can only add decimal point to base-10 literals")
 			}
 			s.nextch()
 			seenPoint = true
 		}
+		if prefix == '0' && (seenPoint || lower(s.ch) == 'e') {
+			// a leading 0 does not denote an octal float, as in 0.5
+			base, prefix = 10, 0
+		}
 	}
 
 	// fractional part
@@ -451,11 +531,10 @@ func invalidSep(x string) int {
 	d := '.'  // digit, one of '_', '0' (a digit), or '.' (anything else)
 	i := 0
 
-	// a prefix counts as a digit
+	// a prefix does not count as a digit, so '_' may not directly follow it
 	if len(x) >= 2 && x[0] == '0' {
 		x1 = lower(rune(x[1]))
 		if x1 == 'x' || x1 == 'o' || x1 == 'b' {
-			d = '0'
 			i = 2
 		}
 	}
@@ -488,7 +567,8 @@ func invalidSep(x string) int {
 func (s *scanner) char() {
 	s.nextch()
 
-	n := 0
+	n := 0             // number of code points
+	second := rune(-1) // second code point, unless escaped
 loop:
 	for ; ; n++ {
 		switch s.ch {
@@ -496,7 +576,12 @@ loop:
 			if n == 0 {
 				s.errorf("empty character literal or unescaped '")
 			} else if n != 1 {
-				s.errorAtf(0, "more than one character in character literal")
+				// a base character followed by a combining mark is one
+				// character to the reader, but two code points
+				if second >= 0 && unicode.Is(unicode.Mn, second) {
+					s.errorAtf(0, "more than one code point in character literal (%U is a combining mark)", second)
+				}
+				s.errorAtf(0, "more than one code point in character literal")
 			}
 			s.nextch()
 			break loop
@@ -510,6 +595,9 @@ loop:
 		if s.ch < 0 {
 			s.errorAtf(0, "character literal not terminated")
 		}
+		if n == 1 {
+			second = s.ch
+		}
 		s.nextch()
 	}
 
@@ -545,14 +633,14 @@ loop:
 
 func (s *scanner) comment() {
 	ch := s.ch
-	s.next()
+	s.nextch()
 	if ch == '/' {
 		for s.ch >= 0 && s.ch != '\n' {
 			s.nextch()
 		}
+		s.directive()
 	} else {
-		// s.ch == '*'
-		s.nextch()
+		// ch == '*'
 		lev := 1
 		for s.ch >= 0 && lev > 0 {
 			switch s.ch {
@@ -578,6 +666,14 @@ func (s *scanner) comment() {
 	}
 }
 
+// directive records the line comment just read if it is a directive.
+func (s *scanner) directive() {
+	text := strings.TrimSpace(string(s.segment()[len("//"):]))
+	if text, ok := strings.CutPrefix(text, "cobalt:"); ok {
+		s.directives = append(s.directives, Directive{s.at(s.line, s.col), text})
+	}
+}
+
 func (s *scanner) escape(quote rune) {
 	var n int
 	var base, max uint32
@@ -616,6 +712,10 @@ func (s *scanner) escape(quote rune) {
 			d = uint32(lower(s.ch)) - 'a' + 10
 		}
 		if d >= base {
+			if s.ch == '_' {
+				// unlike in number literals, digits cannot be separated
+				s.errorf("'_' not allowed in escape sequence")
+			}
 			s.errorf("invalid character %q in %s escape", s.ch, baseName(int(base)))
 		}
 		// d < base
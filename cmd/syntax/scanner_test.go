@@ -0,0 +1,319 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// parseError parses src and returns the message of the resulting error, or
+// the empty string if there is none.
+func parseError(src string) string {
+	_, err := Parse(strings.NewReader(src), "test.co")
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestEOFPosition(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{"const x = 1", "test.co:1:12: expected semicolon"},
+		{"const x = 1\n", "test.co:1:12: expected semicolon"},
+		{"const x = 1\n\n", "test.co:2:1: expected semicolon"},
+		{"const c = 'a", "test.co:1:11: character literal not terminated"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{"/**/ const x = 1;", ""},
+		{"/* a */ const x = 1; // b", ""},
+		{"//* a\nconst x = 1;", ""},
+		{"const x = 1;\n/* a", "test.co:2:1: comment not terminated"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestDigitSeparators(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{"const x = 1_000;", ""},
+		{"const x = 0b1_0;", ""},
+		{"const x = 0x_1;", "test.co:1:13: '_' must separate successive digits"},
+		{"const x = 0b_1;", "test.co:1:13: '_' must separate successive digits"},
+		{"const x = 1_;", "test.co:1:12: '_' must separate successive digits"},
+		{"const x = 1__2;", "test.co:1:13: '_' must separate successive digits"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestInvalidDigits(t *testing.T) {
+	// errors point at the invalid digit, after any prefix and separators
+	for _, test := range []struct{ src, err string }{
+		{"const x = 019;", "test.co:1:13: invalid digit '9' in octal literal"},
+		{"const x = 0o19;", "test.co:1:14: invalid digit '9' in octal literal"},
+		{"const x = 0_19;", "test.co:1:14: invalid digit '9' in octal literal"},
+		{"const x = 01_9;", "test.co:1:14: invalid digit '9' in octal literal"},
+		{"const x = 0b102;", "test.co:1:15: invalid digit '2' in binary literal"},
+		{"const x = 0o;", "test.co:1:13: octal literal has no digits"},
+		{"const x = 09.5;", ""}, // a float, not an octal
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestEscapeSeparators(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{`const x = '\x41';`, ""},
+		{`const x = '\x4_1';`, "test.co:1:15: '_' not allowed in escape sequence"},
+		{`const x = '\1_1';`, "test.co:1:14: '_' not allowed in escape sequence"},
+		{`const x = '\u00_41';`, "test.co:1:16: '_' not allowed in escape sequence"},
+		{`const x = '\_';`, "test.co:1:13: unknown escape"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+// scanAll scans src to the end, discarding all tokens.
+func scanAll(src string) {
+	var s scanner
+	s.init(strings.NewReader(src), "test.co")
+	defer s.release()
+	for s.next(); s.tok != _EOF; s.next() {
+	}
+}
+
+func TestInternedNames(t *testing.T) {
+	var s scanner
+	s.init(strings.NewReader("alpha beta alpha"), "test.co")
+	defer s.release()
+	var names []string
+	for s.next(); s.tok != _EOF; s.next() {
+		names = append(names, s.lit)
+	}
+	if len(names) != 3 || names[0] != "alpha" || names[1] != "beta" || names[2] != "alpha" {
+		t.Fatalf("got names %q", names)
+	}
+	if unsafe.StringData(names[0]) != unsafe.StringData(names[2]) {
+		t.Errorf("repeated name %q is not shared", names[0])
+	}
+
+	// repeated names must not allocate, so the number of allocations does
+	// not depend on the number of lines
+	line := "const alpha = beta + gamma * delta;\n"
+	allocs := func(n int) float64 {
+		src := strings.Repeat(line, n)
+		return testing.AllocsPerRun(10, func() { scanAll(src) })
+	}
+	if few, many := allocs(1), allocs(1000); many > few {
+		t.Errorf("got %v allocations for 1000 lines, want at most %v as for 1 line", many, few)
+	}
+}
+
+func BenchmarkScanRepeatedNames(b *testing.B) {
+	src := strings.Repeat("const alpha = beta + gamma * delta;\n", 1000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		scanAll(src)
+	}
+}
+
+func TestSourceBufferReuse(t *testing.T) {
+	// a reused buffer still holds the bytes of the previous source, which
+	// must not leak into the next one
+	long := strings.Repeat("const abcdefgh = 1;\n", 100)
+	for _, src := range []string{long, "const x = 1;", long, "const y = 2"} {
+		err := parseError(src)
+		if src == "const y = 2" {
+			if want := "test.co:1:12: expected semicolon"; err != want {
+				t.Errorf("%q: got %q, want %q", src, err, want)
+			}
+		} else if err != "" {
+			t.Errorf("%q: %s", src, err)
+		}
+	}
+
+	// parsing a small file reuses a pooled buffer rather than allocating one
+	const n = 100
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < n; i++ {
+		parseSmall()
+	}
+	runtime.ReadMemStats(&after)
+	if bytes := (after.TotalAlloc - before.TotalAlloc) / n; bytes >= 4<<10 {
+		t.Errorf("got %d bytes allocated per parse, want less than the 4K buffer", bytes)
+	}
+}
+
+func parseSmall() {
+	Parse(strings.NewReader("const x = 1; var y: int32 = x;"), "test.co")
+}
+
+func BenchmarkParseSmall(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseSmall()
+	}
+}
+
+// longTokens returns sources holding a single token, or comment, of about n
+// bytes.
+func longTokens(n int) []string {
+	return []string{
+		"const x = " + strings.Repeat("1", n) + ";",
+		"const " + strings.Repeat("x", n) + " = 1;",
+		"// " + strings.Repeat("x", n) + "\nconst x = 1;",
+	}
+}
+
+func TestLongTokens(t *testing.T) {
+	// the buffer grows geometrically, so scanning a long token allocates a
+	// small multiple of its size rather than copying the buffer repeatedly
+	const n = 8 << 20
+	for i, src := range longTokens(n) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := parseError(src)
+		runtime.ReadMemStats(&after)
+		if per := (after.TotalAlloc - before.TotalAlloc) / n; per >= 8 {
+			t.Errorf("source %d: got %d bytes allocated per source byte, want less than 8", i, per)
+		}
+		want := [...]string{"test.co:1:11: excessively long number", "test.co:1:7: excessively long name", ""}[i]
+		if err != want {
+			t.Errorf("source %d: got error %q, want %q", i, err, want)
+		}
+	}
+}
+
+func BenchmarkLongTokens(b *testing.B) {
+	srcs := longTokens(4 << 20)
+	b.ReportAllocs()
+	b.SetBytes(int64(3 * len(srcs[0])))
+	for i := 0; i < b.N; i++ {
+		for _, src := range srcs {
+			Parse(strings.NewReader(src), "test.co")
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	f, err := Parse(strings.NewReader("// cobalt:ignore unused\nconst x = 1; // cobalt:ignore shadow\n// not a directive\n/* cobalt:ignore cast */"), "test.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range f.Directives {
+		got = append(got, d.Pos.String()+" "+d.Text)
+	}
+	want := []string{"test.co:1:1 ignore unused", "test.co:2:14 ignore shadow"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got directives %q, want %q", got, want)
+	}
+}
+
+func TestTabWidth(t *testing.T) {
+	const src = "\tconst x =\t\t;"
+	for _, test := range []struct {
+		width int
+		err   string
+	}{
+		{0, "test.co:1:13: expected an expression"},
+		{1, "test.co:1:13: expected an expression"},
+		{4, "test.co:1:21: expected an expression"},
+		{8, "test.co:1:33: expected an expression"},
+	} {
+		conf := Config{TabWidth: test.width}
+		_, err := conf.Parse(strings.NewReader(src), "test.co")
+		if err == nil || err.Error() != test.err {
+			t.Errorf("tab width %d: got error %v, want %s", test.width, err, test.err)
+		}
+	}
+
+	// the tab width is a per-parse option, the default remains unchanged
+	if got, want := parseError(src), "test.co:1:13: expected an expression"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	in := (&Config{TabWidth: 4}).NewIncremental("repl")
+	in.Feed([]byte("\tx = ;\n"))
+	if _, err := in.Next(); err == nil || err.Error() != "repl:1:9: expected an expression" {
+		t.Errorf("incremental: got error %v, want repl:1:9: expected an expression", err)
+	}
+}
+
+func TestCharLiterals(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{"const c = 'a';", ""},
+		{"const c = '\u00e9';", ""}, // a single, multi-byte code point
+		{"const c = '😀';", ""},
+		{`const c = '\U0001F600';`, ""},
+		{"const c = 'ab';", "test.co:1:11: more than one code point in character literal"},
+		{"const c = 'e\u0301';", "test.co:1:11: more than one code point in character literal (U+0301 is a combining mark)"},
+		{`const c = '\ud800';`, "test.co:1:18: escape is invalid Unicode code point U+D800"},
+		{"const c = '';", "test.co:1:12: empty character literal or unescaped '"},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestReservedWords(t *testing.T) {
+	for _, test := range []struct{ src, err string }{
+		{"const f = proc() { for; };", "test.co:1:20: for is reserved but not yet implemented"},
+		{"const f = proc() {\n\tif x {} };", "test.co:2:2: if is reserved but not yet implemented"},
+		{"const import = 1;", "test.co:1:7: import is reserved but not yet implemented"},
+		// only the exact words are reserved
+		{"const format = 1; const If = 2;", ""},
+	} {
+		if got := parseError(test.src); got != test.err {
+			t.Errorf("%q: got %q, want %q", test.src, got, test.err)
+		}
+	}
+}
+
+func TestInvalidCharRecovery(t *testing.T) {
+	// stray characters are skipped and reported, but the file is still parsed
+	f, err := Parse(strings.NewReader("const a = 1; @ const b = 2;\nconst c = @3;"), "test.co")
+	if want := "test.co:1:14: invalid character U+0040 '@'\ntest.co:2:11: invalid character U+0040 '@'"; err == nil || err.Error() != want {
+		t.Errorf("got error %q, want %q", err, want)
+	}
+	if f == nil {
+		t.Fatal("no file returned")
+	}
+	var got []string
+	for _, d := range f.DeclList {
+		got = append(got, Sexpr(d))
+	}
+	want := []string{"(const (name a) nil (lit 1))", "(const (name b) nil (lit 2))", "(const (name c) nil (lit 3))"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got declarations %q, want %q", got, want)
+	}
+
+	// other syntax errors still stop parsing
+	if f, err := Parse(strings.NewReader("const a = @;"), "test.co"); f != nil || err == nil {
+		t.Errorf("got file %v and error %v, want no file and an error", f, err)
+	}
+}
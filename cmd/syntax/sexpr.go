@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the rendering of syntax trees as S-expressions.
+
+package syntax
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Sexpr returns a compact textual form of the syntax tree rooted at n, in the
+// form of a Lisp-style S-expression, such as
+//
+//	(const (name x) nil (+ (name y) (lit 1)))
+//
+// Each node is written as a list starting with the kind of node, followed by
+// its children in the order of their fields. Absent children are written as
+// nil, and lists of names with more than one element as (list ...). Postfix
+// increments and decrements are headed by post++ and post-- respectively.
+// Sexpr is meant for tests and debugging; positions are omitted.
+func Sexpr(n Node) string {
+	var p sexprPrinter
+	p.node(n)
+	return p.String()
+}
+
+type sexprPrinter struct{ strings.Builder }
+
+// list writes a list headed by head, followed by the nodes in args.
+func (p *sexprPrinter) list(head string, args ...Node) {
+	p.WriteByte('(')
+	p.WriteString(head)
+	for _, n := range args {
+		p.WriteByte(' ')
+		p.node(n)
+	}
+	p.WriteByte(')')
+}
+
+func (p *sexprPrinter) node(n Node) {
+	// absent children are usually typed nils, such as a nil *Name
+	if n == nil || reflect.ValueOf(n).IsNil() {
+		p.WriteString("nil")
+		return
+	}
+
+	switch n := n.(type) {
+	// files and declarations
+	case *File:
+		p.list("file", nodes(n.DeclList)...)
+	case *ConstDecl:
+		p.list("const", names(n.NameList), n.Type, n.Values)
+	case *VarDecl:
+		p.list("var", names(n.NameList), n.Type, n.Values)
+	case *TypeDecl:
+		p.list("type", n.Name, n.Type)
+	case *EnumDecl:
+		p.list("enum", append([]Node{n.Name}, nodes(n.List)...)...)
+	case *Enumerator:
+		p.list("enumerator", n.Name, n.Value)
+
+	// expressions
+	case *Name:
+		p.list("name " + n.Value)
+	case *LiteralExpr:
+		p.list("lit " + n.Value)
+	case *NoneExpr:
+		p.WriteString("none")
+	case *CompoundExpr:
+		p.list("compound", nodes(n.List)...)
+	case *AssignExpr:
+		p.list("assign", n.Lhs, n.Rhs)
+	case *ProcExpr:
+		p.list("proc", n.Type, n.Body)
+	case *Operation:
+		switch {
+		case n.Lhs == nil:
+			p.list(n.Op.String(), n.Rhs) // prefix
+		case n.Rhs == nil && (n.Op == Inc || n.Op == Dec):
+			p.list("post"+n.Op.String(), n.Lhs)
+		case n.Rhs == nil:
+			p.list(n.Op.String(), n.Lhs) // postfix
+		default:
+			p.list(n.Op.String(), n.Lhs, n.Rhs)
+		}
+	case *TernaryExpr:
+		p.list("?", n.Cond, n.A, n.B)
+	case *CallExpr:
+		p.list("call", append([]Node{n.Proc}, nodes(n.ArgList)...)...)
+	case *SelectorExpr:
+		p.list("sel", n.X, n.Sel)
+	case *CastExpr:
+		p.list("cast", n.Type, n.X)
+	case *IndexExpr:
+		p.list("index", n.X, n.Index)
+	case *ListExpr:
+		p.list("list", nodes(n.List)...)
+	case *PointerType:
+		if n.Const {
+			p.list("ptr const", n.Elem)
+		} else {
+			p.list("ptr", n.Elem)
+		}
+	case *OptionType:
+		p.list("option", n.Elem)
+	case *ArrayType:
+		p.list("array", n.Len, n.Elem)
+	case *ProcType:
+		p.WriteString("(proctype ")
+		p.list("params", nodes(n.ParamList)...)
+		p.WriteByte(' ')
+		p.node(n.Result)
+		p.WriteByte(')')
+	case *StructType:
+		p.list("struct", nodes(n.FieldList)...)
+	case *Field:
+		head := "field"
+		if n.Const {
+			head += " const"
+		}
+		p.list(head, n.Name, n.Type)
+
+	// statements
+	case *BlockStmt:
+		p.list("block", nodes(n.StmtList)...)
+	case *ExprStmt:
+		p.list("expr", n.X)
+	case *DeclStmt:
+		p.node(n.D)
+	case *AssignStmt:
+		op := "="
+		if n.Op != 0 {
+			op = n.Op.String() + op
+		}
+		p.list(op, n.Lhs, n.Rhs)
+	case *IncDecStmt:
+		p.list(n.Op.String(), n.X)
+	case *ReturnStmt:
+		if n.Result == nil {
+			p.list("return")
+		} else {
+			p.list("return", n.Result)
+		}
+	case *LabeledStmt:
+		p.list("label", n.Label, n.Stmt)
+	case *DeferStmt:
+		p.list("defer", n.Call)
+	case *GotoStmt:
+		p.list("goto", n.Label)
+
+	default:
+		panic(fmt.Sprintf("syntax: unexpected node %T", n))
+	}
+}
+
+// nodes converts list to a slice of Node.
+func nodes[N Node](list []N) []Node {
+	res := make([]Node, len(list))
+	for i, n := range list {
+		res[i] = n
+	}
+	return res
+}
+
+// names returns the single name in list, or a ListExpr of all names.
+func names(list []*Name) Node {
+	if len(list) == 1 {
+		return list[0]
+	}
+	x := new(ListExpr)
+	for _, n := range list {
+		x.List = append(x.List, n)
+	}
+	return x
+}
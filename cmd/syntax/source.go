@@ -6,7 +6,9 @@
 // tool ("src/cmd/compile/internal/syntax/source.go").
 //
 // There have been made slight changes to incorporate the use of the bail-out
-// mechanism implemented in package base but for the rest remains untouched.
+// mechanism implemented in package base, to reuse source buffers across
+// parses, and to grow large buffers geometrically, but for the rest remains
+// untouched.
 //
 // Original source: https://github.com/golang/go/blob/master/src/cmd/compile/internal/syntax/source.go
 
@@ -22,6 +24,8 @@ import (
 	"cobalt/base"
 	"cobalt/src"
 	"io"
+	"math/bits"
+	"sync"
 	"unicode/utf8"
 )
 
@@ -59,8 +63,11 @@ type source struct {
 	ioerr     error  // pending I/O error, or nil
 	b, r, e   int    // buffer indices (see comment above)
 	line, col uint   // source position of ch (0-based)
+	offs      uint   // byte offset of ch
+	eol       uint   // column of the most recently read newline (0-based)
 	ch        rune   // most recently read character
 	chw       int    // width of ch
+	tabwidth  uint   // columns between tab stops, or 0 (see nextCol)
 }
 
 const sentinel = utf8.RuneSelf
@@ -69,20 +76,51 @@ func (s *source) init(in io.Reader, file string) {
 	s.in = in
 	s.file = file
 
-	s.buf = make([]byte, nextSize(0))
+	s.buf = getBuf(nextSize(0))
 	s.buf[0] = sentinel
 	s.ioerr = nil
 	s.b, s.r, s.e = -1, 0, 0
 	s.line, s.col = 0, 0
+	s.offs = 0
+	s.eol = 0
 	s.ch = ' '
 	s.chw = 0
 }
 
+// release returns the source buffer to the buffer pool. The source must not
+// be used after calling release, unless it is initialized again.
+func (s *source) release() {
+	putBuf(s.buf)
+	s.in = nil
+	s.buf = nil
+}
+
 // starting points for line and column numbers
 const linebase = 1
 const colbase = 1
 
-func (s *source) pos() (line, col uint) { return linebase + s.line, colbase + s.col }
+// nextCol returns the column following a character ch of width w at column
+// col, with tab stops every tabwidth columns. Columns are 0-based. A tab width
+// below 2 counts a tab like any other byte.
+func nextCol(col uint, ch rune, w int, tabwidth uint) uint {
+	if ch == '\t' && tabwidth > 1 {
+		return col - col%tabwidth + tabwidth
+	}
+	return col + uint(w)
+}
+
+// pos returns the (line, col) source position of s.ch.
+//
+// At EOF directly following a newline, s.ch is positioned at the start of a
+// line that does not exist. In that case pos returns the position right after
+// the last character of the last line instead, so that diagnostics at EOF
+// point at actual source code.
+func (s *source) pos() (line, col uint) {
+	if s.ch < 0 && s.line > 0 && s.col == 0 {
+		return linebase + s.line - 1, colbase + s.eol
+	}
+	return linebase + s.line, colbase + s.col
+}
 
 func (s *source) at(line, col uint) src.Pos       { return src.MakePos(s.file, line, col) }
 func (s *source) errorAt(pos src.Pos, msg string) { base.Bailout(Error{pos, msg}) }
@@ -96,10 +134,13 @@ func (s *source) stop()           { s.b = -1 }
 func (s *source) segment() []byte { return s.buf[s.b : s.r-s.chw] }
 
 func (s *source) nextch() {
-	s.col += uint(s.chw)
+	s.offs += uint(s.chw)
 	if s.ch == '\n' {
+		s.eol = s.col
 		s.line++
 		s.col = 0
+	} else {
+		s.col = nextCol(s.col, s.ch, s.chw, s.tabwidth)
 	}
 
 	// fast common case: at least one ASCII character
@@ -149,8 +190,10 @@ func (s *source) fill() {
 
 	// grow buffer or move content down
 	if len(content)*2 > len(s.buf) {
-		s.buf = make([]byte, nextSize(len(s.buf)))
-		copy(s.buf, content)
+		buf := getBuf(nextSize(len(s.buf)))
+		copy(buf, content)
+		putBuf(s.buf)
+		s.buf = buf
 	} else if b > 0 {
 		copy(s.buf, content)
 	}
@@ -177,6 +220,10 @@ func (s *source) fill() {
 }
 
 // nextSize returns the next bigger size for a buffer of a given size.
+//
+// Beyond 1M, buffers grow by half their size rather than by a fixed amount,
+// such that scanning a single huge token, which must be kept in the buffer
+// as a whole, copies a linear rather than a quadratic number of bytes.
 func nextSize(size int) int {
 	const min = 4 << 10 // 4K: minimum buffer size
 	const max = 1 << 20 // 1M: maximum buffer size which is still doubled
@@ -186,5 +233,44 @@ func nextSize(size int) int {
 	if size <= max {
 		return size << 1
 	}
-	return size + max
+	return size + size>>1
+}
+
+// Source buffers are pooled by size class, such that parsing many (small)
+// files does not allocate a fresh buffer every time. The size classes are the
+// powers of two produced by nextSize, from 4K up to and including 2M. Larger
+// buffers are not pooled and are left to the garbage collector.
+const minPoolShift, maxPoolShift = 12, 21 // 4K, 2M
+
+var bufPool [maxPoolShift - minPoolShift + 1]sync.Pool // of *[]byte
+
+// poolIndex returns the index into bufPool for a buffer of the given size,
+// or -1 if buffers of that size are not pooled.
+func poolIndex(size int) int {
+	if size <= 0 || size&(size-1) != 0 {
+		return -1 // not a power of two
+	}
+	shift := bits.TrailingZeros(uint(size))
+	if shift < minPoolShift || shift > maxPoolShift {
+		return -1
+	}
+	return shift - minPoolShift
+}
+
+// getBuf returns a buffer of the given size, reusing a pooled buffer if one
+// is available. The contents of the returned buffer are undefined.
+func getBuf(size int) []byte {
+	if i := poolIndex(size); i >= 0 {
+		if buf, ok := bufPool[i].Get().(*[]byte); ok {
+			return *buf
+		}
+	}
+	return make([]byte, size)
+}
+
+// putBuf returns buf to the buffer pool, if its size is pooled.
+func putBuf(buf []byte) {
+	if i := poolIndex(len(buf)); i >= 0 {
+		bufPool[i].Put(&buf)
+	}
 }
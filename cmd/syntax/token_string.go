@@ -27,17 +27,23 @@ func _() {
 	_ = x[_Dot-17]
 	_ = x[_Cond-18]
 	_ = x[keywordFirst-19]
-	_ = x[_Const-20]
-	_ = x[_Proc-21]
-	_ = x[_Return-22]
-	_ = x[_Struct-23]
-	_ = x[_Var-24]
-	_ = x[keywordLast-25]
+	_ = x[_As-20]
+	_ = x[_Const-21]
+	_ = x[_Defer-22]
+	_ = x[_Enum-23]
+	_ = x[_Goto-24]
+	_ = x[_None-25]
+	_ = x[_Proc-26]
+	_ = x[_Return-27]
+	_ = x[_Struct-28]
+	_ = x[_Type-29]
+	_ = x[_Var-30]
+	_ = x[keywordLast-31]
 }
 
-const _token_name = "EOFnameliteralopop==*([{)]},;:.?constprocreturnstructvar"
+const _token_name = "EOFnameliteralopop==*([{)]},;:.?asconstdeferenumgotononeprocreturnstructtypevar"
 
-var _token_index = [...]uint8{0, 3, 7, 14, 16, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 32, 37, 41, 47, 53, 56, 56}
+var _token_index = [...]uint8{0, 3, 7, 14, 16, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 32, 34, 39, 44, 48, 52, 56, 60, 66, 72, 76, 79, 79}
 
 func (i token) String() string {
 	i -= 1
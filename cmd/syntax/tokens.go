@@ -40,14 +40,36 @@ const (
 
 	// keywords, more will be added over time.
 	keywordFirst //
+	_As          // as
 	_Const       // const
+	_Defer       // defer
+	_Enum        // enum
+	_Goto        // goto
+	_None        // none
 	_Proc        // proc
 	_Return      // return
 	_Struct      // struct
+	_Type        // type
 	_Var         // var
 	keywordLast  //
 )
 
+// IsKeyword reports whether tok is a keyword, such as "const" or "return".
+func (tok token) IsKeyword() bool {
+	return keywordFirst < tok && tok < keywordLast
+}
+
+// IsLiteral reports whether tok is a name or a literal, i.e. whether it
+// carries the text of the token with it.
+func (tok token) IsLiteral() bool {
+	return tok == _Name || tok == _Literal
+}
+
+// IsDelimiter reports whether tok is a delimiter, such as "(" or ";".
+func (tok token) IsDelimiter() bool {
+	return _Lparen <= tok && tok <= _Cond
+}
+
 //go:generate stringer -type Literal tokens.go
 
 // LitKind represents the kind of a basic literal.
@@ -107,12 +129,64 @@ const (
 	OperatorMax
 )
 
+// Symbol returns the spelling of op in source code, such as "+" or "<<", for
+// use in diagnostics. Contrary to String, it returns the empty string if op is
+// not an operator.
+func (op Operator) Symbol() string {
+	if op == 0 || op >= OperatorMax {
+		return ""
+	}
+	return op.String()
+}
+
 // Operator precedences
 const (
 	_ = iota
 	precOrOr
 	precAndAnd
+	precAs // x as T, which is not an operator
 	precCmp
 	precAdd
 	precMul
 )
+
+// Precedence returns the precedence of op as a binary operator, where higher
+// values bind more tightly. It returns 0 for operators which are only unary.
+func (op Operator) Precedence() int {
+	switch {
+	case op == OrOr:
+		return precOrOr
+	case op == AndAnd:
+		return precAndAnd
+	case Eql <= op && op <= Geq:
+		return precCmp
+	case Add <= op && op <= Xor:
+		return precAdd
+	case Mul <= op && op <= Shr:
+		return precMul
+	}
+	return 0
+}
+
+// IsComparison reports whether op is a comparison operator, such as "==" or
+// "<", which yields a boolean.
+func (op Operator) IsComparison() bool {
+	return Eql <= op && op <= Geq
+}
+
+// IsUnary reports whether op is exclusively a unary operator. Note that "+",
+// "-" and "&" are binary operators which may also be used as prefix operators.
+func (op Operator) IsUnary() bool {
+	return Not <= op && op <= Deref
+}
+
+// IsBitwise reports whether op operates on the individual bits of its
+// operands, which are "~", "&", "|" and "^". Shifts are not considered
+// bitwise operators.
+func (op Operator) IsBitwise() bool {
+	switch op {
+	case Not, And, Or, Xor:
+		return true
+	}
+	return false
+}
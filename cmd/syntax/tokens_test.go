@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package syntax
+
+import "testing"
+
+func TestOperatorSymbol(t *testing.T) {
+	want := map[Operator]string{
+		Not: "~", LNot: "!", Inc: "++", Dec: "--", Deref: ".*",
+		OrOr: "||", AndAnd: "&&",
+		Eql: "==", Neq: "!=", Lss: "<", Leq: "<=", Gtr: ">", Geq: ">=",
+		Add: "+", Sub: "-", Or: "|", Xor: "^",
+		Mul: "*", Div: "/", Rem: "%", And: "&", Shl: "<<", Shr: ">>",
+	}
+	for op := Operator(1); op < OperatorMax; op++ {
+		if got := op.Symbol(); got != want[op] {
+			t.Errorf("operator %d: got symbol %q, want %q", op, got, want[op])
+		}
+	}
+	for _, op := range []Operator{0, OperatorMax, OperatorMax + 1} {
+		if got := op.Symbol(); got != "" {
+			t.Errorf("operator %d: got symbol %q, want none", op, got)
+		}
+	}
+}
+
+func TestOperatorMetadata(t *testing.T) {
+	for _, test := range []struct {
+		op                  Operator
+		prec                int
+		cmp, unary, bitwise bool
+	}{
+		{Not, 0, false, true, true},
+		{LNot, 0, false, true, false},
+		{Inc, 0, false, true, false},
+		{Dec, 0, false, true, false},
+		{Deref, 0, false, true, false},
+		{OrOr, precOrOr, false, false, false},
+		{AndAnd, precAndAnd, false, false, false},
+		{Eql, precCmp, true, false, false},
+		{Neq, precCmp, true, false, false},
+		{Lss, precCmp, true, false, false},
+		{Leq, precCmp, true, false, false},
+		{Gtr, precCmp, true, false, false},
+		{Geq, precCmp, true, false, false},
+		{Add, precAdd, false, false, false},
+		{Sub, precAdd, false, false, false},
+		{Or, precAdd, false, false, true},
+		{Xor, precAdd, false, false, true},
+		{Mul, precMul, false, false, false},
+		{Div, precMul, false, false, false},
+		{Rem, precMul, false, false, false},
+		{And, precMul, false, false, true},
+		{Shl, precMul, false, false, false},
+		{Shr, precMul, false, false, false},
+	} {
+		if got := test.op.Precedence(); got != test.prec {
+			t.Errorf("%s: got precedence %d, want %d", test.op, got, test.prec)
+		}
+		if got := test.op.IsComparison(); got != test.cmp {
+			t.Errorf("%s: IsComparison() = %v, want %v", test.op, got, test.cmp)
+		}
+		if got := test.op.IsUnary(); got != test.unary {
+			t.Errorf("%s: IsUnary() = %v, want %v", test.op, got, test.unary)
+		}
+		if got := test.op.IsBitwise(); got != test.bitwise {
+			t.Errorf("%s: IsBitwise() = %v, want %v", test.op, got, test.bitwise)
+		}
+	}
+}
+
+func TestTokenClasses(t *testing.T) {
+	for tok := _EOF; tok < keywordFirst; tok++ {
+		keyword := false
+		literal := tok == _Name || tok == _Literal
+		delim := tok >= _Lparen && tok <= _Cond
+		if got := tok.IsKeyword(); got != keyword {
+			t.Errorf("%s: IsKeyword() = %v, want %v", tok, got, keyword)
+		}
+		if got := tok.IsLiteral(); got != literal {
+			t.Errorf("%s: IsLiteral() = %v, want %v", tok, got, literal)
+		}
+		if got := tok.IsDelimiter(); got != delim {
+			t.Errorf("%s: IsDelimiter() = %v, want %v", tok, got, delim)
+		}
+	}
+
+	for tok := keywordFirst + 1; tok < keywordLast; tok++ {
+		if !tok.IsKeyword() || tok.IsLiteral() || tok.IsDelimiter() {
+			t.Errorf("%s is not classified as keyword only", tok)
+		}
+	}
+
+	if !_Const.IsKeyword() || _Const.IsLiteral() || _Name.IsKeyword() || !_Name.IsLiteral() {
+		t.Errorf("const and name are misclassified")
+	}
+	if keywordFirst.IsKeyword() || keywordLast.IsKeyword() {
+		t.Errorf("the keyword bounds are keywords")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements assignability and conversions of operands.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/syntax"
+	"fmt"
+)
+
+// assignment reports an error if x is not assignable to a variable of type T,
+// in which case x.mode is set to invalid. Constant operands are converted to
+// T if they are representable by it. The context describes the assignment
+// in error messages.
+func (check *checker) assignment(x *operand, T *Type, context string) {
+	if x.mode == invalid || !isValid(T) {
+		x.mode = invalid
+		return
+	}
+
+	if isUntyped(x.typ) {
+		check.implicitType(x, T)
+		if x.mode == invalid {
+			return
+		}
+	}
+
+	if Identical(x.typ, T) {
+		return
+	}
+
+	if AssignableTo(x.typ, T) {
+		x.mode = value
+		x.typ = T
+		return
+	}
+
+	if isValid(x.typ) {
+		check.errorf(x.expr.Pos(), "cannot use %s as %s value in %s", x, T, context)
+	}
+	x.mode = invalid
+}
+
+// assignError reports a mismatch between the number of variables l and the
+// number of values r in an assignment. If rhs is a single call, r is the
+// number of results of the call.
+func (check *checker) assignError(rhs []syntax.Expr, l, r int) {
+	vars := measure(l, "variable")
+	vals := measure(r, "value")
+
+	if len(rhs) == 1 {
+		if call, ok := rhs[0].(*syntax.CallExpr); ok {
+			name := "procedure call"
+			if n, ok := call.Proc.(*syntax.Name); ok {
+				name = n.Value
+			}
+			check.errorf(rhs[0].Pos(), "%s returns %s but %s", name, vals, vars)
+			return
+		}
+	}
+	check.errorf(rhs[0].Pos(), "assignment mismatch: %s but %s", vars, vals)
+}
+
+// resultCount returns the number of values x consists of, where x is the
+// result of a call. As procedures return at most a single value, this is
+// either 0 or 1.
+func resultCount(x *operand) int {
+	if x.mode == novalue {
+		return 0
+	}
+	return 1
+}
+
+// measure returns n followed by unit, pluralized if n != 1.
+func measure(n int, unit string) string {
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
+
+// implicitType converts the untyped operand x to type T, or to the element
+// type of T if T is an optional type. Untyped numeric constants must be
+// representable by T. If x cannot be converted to T, x is left unchanged.
+func (check *checker) implicitType(x *operand, T *Type) {
+	if T.kind == TOPTION {
+		T = T.Elem()
+	}
+
+	switch {
+	case isNumeric(x.typ) && isNumeric(T):
+		if x.mode == constant {
+			check.representable(x, T)
+			return
+		}
+	case isBoolean(x.typ) && isBoolean(T):
+		if x.mode == constant {
+			x.val = x.val.Convert(T.kind)
+		}
+	default:
+		return
+	}
+	x.typ = T
+}
+
+// defaultType converts the untyped operand x to its default type, as used
+// when there is no type to convert x to.
+func (check *checker) defaultType(x *operand) {
+	if x.mode == invalid || !isUntyped(x.typ) {
+		return
+	}
+
+	if x.mode == constant {
+		check.representable(x, DefaultType(x.val))
+	} else {
+		x.typ = Types[TBOOL] // only booleans may be untyped but not constant
+	}
+}
+
+// representable checks that the numeric constant x is representable by the
+// numeric type T, and if so, converts x to T. Otherwise, an error is reported
+// and x.mode is set to invalid.
+//
+// Untyped constants are rounded when converted to float32, which is reported
+// as a warning if the value changes, as for 0.1.
+func (check *checker) representable(x *operand, T *Type) {
+	val, why := representation(x, T)
+	if why != "" {
+		check.errorf(x.expr.Pos(), "constant %s %s %s", x.val, why, T)
+		x.mode = invalid
+		return
+	}
+	if T.kind == TFLOAT32 && isUntyped(x.typ) && !boolVal(val.Binary(syntax.Eql, x.val)) {
+		// print the rounded value in full, as a float32 prints like the original
+		exact := val.Convert(TUNTYPEDFLOAT)
+		check.warnf(x.expr.Pos(), base.WarnPrecision, "constant %s rounded to %s in %s", x.val, exact, T)
+	}
+
+	x.val = val
+	x.typ = T
+}
+
+// representation returns the value of the numeric constant x converted to the
+// numeric type T. If x is not representable by T, it also returns why, as in
+// "overflows" or "truncated to".
+func representation(x *operand, T *Type) (val Value, why string) {
+	to := valueKind(T.kind)
+	switch {
+	case Representable(x.val, to):
+		return x.val.Convert(to), ""
+	case x.typ.kind.IsFloat() && T.kind.IsIntegral():
+		return nil, "truncated to"
+	default:
+		return nil, "overflows"
+	}
+}
+
+// valueKind returns the kind used to represent constant values of kind k,
+// substituting the pointer-sized integers with their fixed-size equivalents.
+func valueKind(k Kind) Kind {
+	switch k {
+	case TINTPTR:
+		if PtrSize == 4 {
+			return TINT32
+		}
+		return TINT64
+	case TUINTPTR:
+		if PtrSize == 4 {
+			return TUINT32
+		}
+		return TUINT64
+	}
+	return k
+}
+
+// conversion type-checks the explicit conversion of x to type T.
+func (check *checker) conversion(x *operand, T *Type) {
+	if !ConvertibleTo(x.typ, T) {
+		check.errorf(x.expr.Pos(), "cannot convert %s to type %s", x, T)
+		x.mode = invalid
+		return
+	}
+
+	if x.mode == constant && (isNumeric(T) || isBoolean(T) || Identical(x.typ, T)) {
+		// explicit conversions of constants truncate and wrap around, but
+		// arbitrary-precision integers cannot be converted if they overflow
+		val, err := ConvertValue(x.val, T)
+		if err != nil {
+			check.errorf(x.expr.Pos(), "cannot convert %s to type %s (overflows)", x, T)
+			x.mode = invalid
+			return
+		}
+		x.val = val
+		x.typ = T
+		return
+	}
+
+	x.mode = value
+	x.typ = T
+}
@@ -13,3 +13,9 @@ const (
 	BuiltinTypeof
 	BuiltinSizeof
 )
+
+// builtinNames maps built-in procedures to their names, for error messages.
+var builtinNames = [...]string{
+	BuiltinTypeof: "typeof",
+	BuiltinSizeof: "sizeof",
+}
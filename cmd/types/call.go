@@ -0,0 +1,188 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the type-checking of procedure calls, including calls
+// to built-in procedures.
+
+package types
+
+import (
+	"cobalt/syntax"
+	"fmt"
+)
+
+func (check *checker) call(x *operand, e *syntax.CallExpr) {
+	check.rawExpr(x, e.Proc, nil)
+
+	switch x.mode {
+	case invalid:
+		check.useExprs(e.ArgList)
+		return
+
+	case builtin:
+		check.builtin(x, e)
+		return
+
+	case typexpr:
+		if !e.Paren {
+			check.errorf(e.Pos(), "invalid call of type %s, use a cast instead", x.typ)
+			check.useExprs(e.ArgList)
+			x.mode = invalid
+			return
+		}
+		// (T)(x) is a cast
+		T := x.typ
+		switch n := len(e.ArgList); {
+		case n == 0:
+			check.errorf(e.Pos(), "missing operand in cast to %s", T)
+			x.mode = invalid
+		case n > 1:
+			check.errorf(e.ArgList[1].Pos(), "too many operands in cast to %s", T)
+			check.useExprs(e.ArgList)
+			x.mode = invalid
+		default:
+			check.castTo(x, T, e.ArgList[0])
+		}
+		return
+
+	case novalue:
+		check.errorf(e.Pos(), "%s used as value", x)
+		check.useExprs(e.ArgList)
+		x.mode = invalid
+		return
+	}
+
+	if x.typ.kind != TPROC {
+		check.errorf(e.Pos(), "invalid operation: cannot call non-procedure %s", x)
+		check.useExprs(e.ArgList)
+		x.mode = invalid
+		return
+	}
+
+	check.recordCall(e.Proc)
+	sig := x.typ.extra.(*Signature)
+	check.arguments(e, sig)
+
+	if isVoid(sig.Result) {
+		x.mode = novalue
+		x.typ = nil
+		return
+	}
+	x.mode = value
+	x.typ = sig.Result
+}
+
+// isCast reports whether the checked call e is a cast (T)(x). The callee of
+// such a call is a parenthesized name, as type literals are parsed as casts
+// right away.
+func (check *checker) isCast(e *syntax.CallExpr) bool {
+	n, ok := e.Proc.(*syntax.Name)
+	if !e.Paren || !ok {
+		return false
+	}
+	sym := check.mod.names[n]
+	if sym == nil {
+		return false
+	}
+	_, ok = sym.extra.(typeValue)
+	return ok && sym.flags&symStatic != 0
+}
+
+// arguments type-checks the arguments of a call to a procedure with the
+// signature sig.
+func (check *checker) arguments(e *syntax.CallExpr, sig *Signature) {
+	if err := CheckCallArity(sig, len(e.ArgList)); err != nil {
+		// report surplus arguments at the first one of them
+		pos := e.Pos()
+		if n := len(sig.Params); len(e.ArgList) > n {
+			pos = e.ArgList[n].Pos()
+		}
+		check.errorf(pos, "%v", err)
+		check.useExprs(e.ArgList)
+		return
+	}
+
+	for i, arg := range e.ArgList {
+		var x operand
+		T := sig.Params[i].Type
+		check.exprWithHint(&x, arg, T)
+
+		// report constants not fitting the parameter in terms of the parameter
+		if x.mode == constant && isUntyped(x.typ) && isNumeric(x.typ) && isValid(T) && isNumeric(T) {
+			if _, why := representation(&x, T); why != "" {
+				check.errorf(arg.Pos(), "constant %s %s %s parameter", x.val, why, T)
+				continue
+			}
+		}
+		check.assignment(&x, T, "argument")
+	}
+}
+
+// builtin type-checks a call to the built-in procedure x.id.
+func (check *checker) builtin(x *operand, e *syntax.CallExpr) {
+	// all built-in procedures take a single argument
+	if err := checkArity(1, len(e.ArgList), "in call to "+builtinNames[x.id]); err != nil {
+		check.errorf(e.Pos(), "%v", err)
+		check.useExprs(e.ArgList)
+		x.mode = invalid
+		return
+	}
+
+	switch x.id {
+	case BuiltinTypeof:
+		// typeof(x)
+		check.expr(x, e.ArgList[0])
+		if x.mode == invalid {
+			return
+		}
+		x.mode = typexpr
+
+	case BuiltinSizeof:
+		// sizeof(x)
+		check.rawExpr(x, e.ArgList[0], nil)
+		check.exclude(x, 1<<novalue|1<<builtin)
+		if x.mode == invalid {
+			return
+		}
+		check.defaultType(x)
+		if x.mode == invalid {
+			return
+		}
+		if !Sizeable(x.typ) {
+			if isValid(x.typ) {
+				check.errorf(x.expr.Pos(), "invalid argument: cannot take size of %s", x)
+			}
+			x.mode = invalid
+			return
+		}
+		x.mode = constant
+		x.val = MakeUint(uint64(x.typ.Size())).Convert(valueKind(TUINTPTR))
+		x.typ = Types[TUINTPTR]
+	}
+}
+
+// CheckCallArity reports whether nargs arguments may be passed to a procedure
+// with the signature sig. As there is no overloading, nor variadic parameters
+// or default arguments, the number of arguments must match the number of
+// parameters exactly.
+func CheckCallArity(sig *Signature, nargs int) error {
+	return checkArity(len(sig.Params), nargs, "in call")
+}
+
+func checkArity(want, got int, context string) error {
+	if got == want {
+		return nil
+	}
+
+	msg := "not enough arguments"
+	if got > want {
+		msg = "too many arguments"
+	}
+
+	noun := "arguments"
+	if want == 1 {
+		noun = "argument"
+	}
+	return fmt.Errorf("%s %s: want %d %s, got %d", msg, context, want, noun, got)
+}
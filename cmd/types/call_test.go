@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestCheckCallArity(t *testing.T) {
+	param := &Field{Type: Types[TINT32]}
+	for _, test := range []struct {
+		params, nargs int
+		err           string
+	}{
+		{0, 0, ""},
+		{2, 2, ""},
+		{2, 1, "not enough arguments in call: want 2 arguments, got 1"},
+		{2, 3, "too many arguments in call: want 2 arguments, got 3"},
+		{1, 0, "not enough arguments in call: want 1 argument, got 0"},
+		{0, 1, "too many arguments in call: want 0 arguments, got 1"},
+	} {
+		params := make([]*Field, test.params)
+		for i := range params {
+			params[i] = param
+		}
+		sig := NewSignature(params, nil).extra.(*Signature)
+
+		var got string
+		if err := CheckCallArity(sig, test.nargs); err != nil {
+			got = err.Error()
+		}
+		if got != test.err {
+			t.Errorf("%d params, %d args: got %q, want %q", test.params, test.nargs, got, test.err)
+		}
+	}
+}
+
+func TestCallArity(t *testing.T) {
+	const f = "const f = proc(a: int32, b: bool) {}; "
+	wantErrors(t, f+"const g = proc() { f(1, true); };")
+	wantErrors(t, f+"const g = proc() { f(1); };", "1:59: not enough arguments in call: want 2 arguments, got 1")
+	wantErrors(t, f+"const g = proc() { f(1, true, 3); };", "1:69: too many arguments in call: want 2 arguments, got 3")
+	wantErrors(t, "const a = sizeof(int32, int8);", "1:17: too many arguments in call to sizeof: want 1 argument, got 2")
+}
+
+func TestArgumentTypes(t *testing.T) {
+	const f = "const f = proc(a: int32, b: bool) {}; var p: *int32; "
+	wantErrors(t, f+"const g = proc() { f(p.*, 1 < 2); };")
+	wantErrors(t, f+"const g = proc() { f(1, 2); };", "1:78: cannot use untyped int constant 2 as bool value in argument")
+	wantErrors(t, f+"const g = proc() { f(p, true); };", "1:75: cannot use p (variable of type *int32) as int32 value in argument")
+	// each mismatched argument is reported at its position
+	wantErrors(t, f+"const g = proc() { f(true, 1); };",
+		"1:75: cannot use true (untyped bool constant true) as int32 value in argument",
+		"1:81: cannot use untyped int constant 1 as bool value in argument")
+}
+
+func TestConstantArguments(t *testing.T) {
+	const f = "const f = proc(a: uint8) {}; var v: int32; "
+	wantErrors(t, f+"const g = proc() { f(200); f(255); f(0); };")
+	wantErrors(t, f+"const g = proc() { f(300); };", "1:65: constant 300 overflows uint8 parameter")
+	wantErrors(t, f+"const g = proc() { f(-1); };", "1:65: constant -1 overflows uint8 parameter")
+	wantErrors(t, f+"const g = proc() { f(1.5); };", "1:65: constant 1.5 truncated to uint8 parameter")
+	// non-constant arguments are not narrowed implicitly
+	wantErrors(t, f+"const g = proc() { f(v); };", "1:65: cannot use v (variable of type int32) as uint8 value in argument")
+}
+
+func TestParenthesizedCallee(t *testing.T) {
+	// (T)(x) is a cast if T denotes a type, and a call otherwise
+	mod := wantErrors(t, "var a, b: int32; var c = (int64)(a + b); const d = (int8)(300 - 100); const e = ((uint8))(255); "+
+		"type T struct{a: int32;}; const t = (T)({.a = 1}); var o = (?int32)(none);")
+	wantConsts(t, mod, "d", "-56", "e", "255", "t", "T{a: 1}")
+	if got := mod.Lookup("c").typ.String(); got != "int64" {
+		t.Errorf("c has type %s, want int64", got)
+	}
+	wantErrors(t, "const f = proc(x: int32) int32 { return x; }; var y = (f)(1); var z = ((f))(2);")
+
+	wantErrors(t, "var x = int64(1);", "1:14: invalid call of type int64, use a cast instead")
+	wantErrors(t, "var x = (int64)();", "1:16: missing operand in cast to int64")
+	wantErrors(t, "var x = (int64)(1, 2);", "1:20: too many operands in cast to int64")
+	wantErrors(t, "var a, b: int32; var x = (a + b)(1);", "1:33: invalid operation: cannot call non-procedure value of type int32")
+
+	// unlike calls, casts are not statements
+	wantErrors(t, "const g = proc() { var x: int32; (int64)(x); (int32)(1); };",
+		"1:41: value of type int64 is not used", "1:53: constant 1 of type int32 is not used")
+	wantErrors(t, "const f = proc() { defer (int32)(1); };", "1:33: defer discards result of constant 1 of type int32")
+}
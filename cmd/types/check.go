@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the entry point of the type checker. The type checker
+// resolves all names in a syntax tree to their symbols, computes the types of
+// all expressions and evaluates constant expressions.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/src"
+	"cobalt/syntax"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Error describes a type-checking error or warning. Contrary to syntax errors,
+// the type checker does not stop at the first error, so multiple errors may be
+// reported for a single source file.
+type Error struct {
+	Pos      src.Pos
+	Msg      string
+	Category string // warning category, see base.WarningEnabled; empty for errors
+}
+
+func (e Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// Summary describes the outcome of type-checking a source file, or the source
+// files of a module.
+type Summary struct {
+	Errors   []Error // errors, in the order they were found
+	Warnings []Error // warnings, in the order they were found
+	NumDecls int     // number of global symbols declared
+}
+
+// String returns a one-line description of s, such as
+// "checked 3 declarations, 1 error, 0 warnings".
+func (s *Summary) String() string {
+	return fmt.Sprintf("checked %s, %s, %s",
+		measure(s.NumDecls, "declaration"),
+		measure(len(s.Errors), "error"),
+		measure(len(s.Warnings), "warning"))
+}
+
+type checker struct {
+	mod   *Module
+	scope *Scope // current scope
+	proc  *Proc  // current procedure, or nil at the global level
+
+	// labels of the current procedure
+	labels map[string]*syntax.LabeledStmt
+
+	// global symbols, in source order, and their declarations
+	order []*Symbol
+	decls map[*Symbol]*declInfo
+
+	// warning categories ignored per line, see ignoreDirectives
+	ignored map[lineKey][]string
+
+	procs []*Proc // procedures checked, see purity
+
+	delayed  []func() // actions to be performed after checking all globals
+	errors   []Error
+	warnings []Error
+}
+
+// A lineKey identifies a line in a source file.
+type lineKey struct {
+	file string
+	line uint
+}
+
+// Check type-checks a source file, declaring all of its global symbols in the
+// scope of mod. It returns a summary of the diagnostics found; the file is
+// well-typed if the summary has no errors.
+func Check(mod *Module, file *syntax.File) *Summary {
+	return CheckFiles(mod, []*syntax.File{file})
+}
+
+// CheckFiles is like [Check], but type-checks the source files of a module as
+// a whole. The global symbols of all files are declared in the same scope of
+// mod, such that a symbol declared in one file may be used in another, and a
+// symbol declared in more than one file is reported as redeclared. The files
+// are processed in order of their file names.
+func CheckFiles(mod *Module, files []*syntax.File) *Summary {
+	check := &checker{
+		mod:   mod,
+		scope: mod.scope,
+		decls: make(map[*Symbol]*declInfo),
+	}
+
+	files = slices.Clone(files)
+	slices.SortStableFunc(files, func(a, b *syntax.File) int {
+		return strings.Compare(a.Pos().Filename(), b.Pos().Filename())
+	})
+	for _, file := range files {
+		check.ignoreDirectives(file.Directives)
+		check.collectDecls(file.DeclList)
+	}
+	for _, sym := range check.order {
+		check.symDecl(sym)
+	}
+
+	// delayed actions may add more delayed actions
+	for i := 0; i < len(check.delayed); i++ {
+		check.delayed[i]()
+	}
+	check.purity()
+
+	return &Summary{
+		Errors:   check.errors,
+		Warnings: check.warnings,
+		NumDecls: len(check.order),
+	}
+}
+
+// later pushes f on to the stack of actions that will be processed later,
+// once all global symbols have been checked.
+func (check *checker) later(f func()) {
+	check.delayed = append(check.delayed, f)
+}
+
+// errorf reports an error at the provided position. Contrary to the parser,
+// the checker does not bail out but continues checking.
+func (check *checker) errorf(pos src.Pos, format string, args ...any) {
+	check.errors = append(check.errors, Error{pos, fmt.Sprintf(format, args...), ""})
+}
+
+// warnf reports a warning of the given category at the provided position,
+// unless the category is suppressed. Warnings do not make the source file
+// invalid.
+func (check *checker) warnf(pos src.Pos, category string, format string, args ...any) {
+	if !base.WarningEnabled(category) || slices.Contains(check.ignored[lineKey{pos.Filename(), pos.Line()}], category) {
+		return
+	}
+	check.warnings = append(check.warnings, Error{pos, fmt.Sprintf(format, args...), category})
+}
+
+// ignoreDirectives records the warning categories ignored by directives of the
+// form "// cobalt:ignore unused,shadow", which apply to the following line.
+func (check *checker) ignoreDirectives(list []syntax.Directive) {
+	for _, d := range list {
+		verb, args, _ := strings.Cut(d.Text, " ")
+		if verb != "ignore" {
+			check.errorf(d.Pos, "unknown directive cobalt:%s", verb)
+			continue
+		}
+		args = strings.TrimSpace(args)
+		if args == "" {
+			check.errorf(d.Pos, "missing warning category in cobalt:ignore")
+			continue
+		}
+		for _, category := range strings.Split(args, ",") {
+			if !base.IsWarning(category) {
+				check.errorf(d.Pos, "unknown warning category %q", category)
+				continue
+			}
+			if check.ignored == nil {
+				check.ignored = make(map[lineKey][]string)
+			}
+			line := lineKey{d.Pos.Filename(), d.Pos.Line() + 1}
+			check.ignored[line] = append(check.ignored[line], category)
+		}
+	}
+}
+
+func (check *checker) openScope(pos, end src.Pos) {
+	check.scope = NewScope(check.scope, pos, end)
+}
+
+func (check *checker) closeScope() {
+	check.scope = check.scope.parent
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/syntax"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func init() {
+	PtrSize = 8
+	Init()
+}
+
+var nmodules int
+
+// checkSource type-checks src as the only file of a new module, and returns
+// the module along with the summary of the check.
+func checkSource(t *testing.T, src string) (*Module, *Summary) {
+	t.Helper()
+	file, err := syntax.Parse(strings.NewReader(src), "test.co")
+	if err != nil {
+		t.Fatalf("%q: %v", src, err)
+	}
+	nmodules++
+	mod := NewModule("test", fmt.Sprintf("test%d", nmodules))
+	return mod, Check(mod, file)
+}
+
+// wantErrors type-checks src and compares the errors reported with want, in
+// order. Each error is written as "line:col: message".
+func wantErrors(t *testing.T, src string, want ...string) *Module {
+	t.Helper()
+	mod, sum := checkSource(t, src)
+	var got []string
+	for _, err := range sum.Errors {
+		got = append(got, fmt.Sprintf("%d:%d: %s", err.Pos.Line(), err.Pos.Col(), err.Msg))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("%q:\ngot  %q\nwant %q", src, got, want)
+	}
+	return mod
+}
+
+// wantWarnings is like wantErrors, but compares the warnings reported.
+func wantWarnings(t *testing.T, src string, want ...string) *Module {
+	t.Helper()
+	mod, sum := checkSource(t, src)
+	var got []string
+	for _, err := range sum.Warnings {
+		got = append(got, fmt.Sprintf("%d:%d: %s", err.Pos.Line(), err.Pos.Col(), err.Msg))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("%q:\ngot  %q\nwant %q", src, got, want)
+	}
+	return mod
+}
+
+func TestSummary(t *testing.T) {
+	_, sum := checkSource(t, "const a: float32 = 16777217; var b: int8 = 300; type T int32;")
+	if got, want := sum.String(), "checked 3 declarations, 1 error, 1 warning"; got != want {
+		t.Errorf("got summary %q, want %q", got, want)
+	}
+	if len(sum.Errors) != 1 || len(sum.Warnings) != 1 || sum.NumDecls != 3 {
+		t.Errorf("got %d errors, %d warnings and %d declarations, want 1, 1 and 3", len(sum.Errors), len(sum.Warnings), sum.NumDecls)
+	}
+
+	wantWarnings(t, "const a: float32 = 16777217;", "1:20: constant 16777217 rounded to 1.6777216e+07 in float32")
+
+	_, sum = checkSource(t, "")
+	if got, want := sum.String(), "checked 0 declarations, 0 errors, 0 warnings"; got != want {
+		t.Errorf("got summary %q, want %q", got, want)
+	}
+}
+
+func TestPrecisionWarning(t *testing.T) {
+	// exactly representable values, float64 and explicit casts do not warn
+	wantWarnings(t, "const a: float32 = 0.5; var b: float32 = 1e10; const c: float64 = 0.1; const d = (float32)0.1;")
+	wantWarnings(t, "const a: float32 = 0.1;", "1:20: constant 0.1 rounded to 0.10000000149011612 in float32")
+	wantErrors(t, "const a: float32 = 1e40;", "1:20: constant 1e+40 overflows float32")
+}
+
+func TestCheckFiles(t *testing.T) {
+	check := func(srcs map[string]string) (*Module, []string) {
+		var files []*syntax.File
+		for name, src := range srcs {
+			file, err := syntax.Parse(strings.NewReader(src), name)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			files = append(files, file)
+		}
+		nmodules++
+		mod := NewModule("test", fmt.Sprintf("test%d", nmodules))
+		var errs []string
+		for _, err := range CheckFiles(mod, files).Errors {
+			errs = append(errs, err.Error())
+		}
+		return mod, errs
+	}
+
+	// symbols of one file are visible in the other, regardless of order
+	mod, errs := check(map[string]string{"b.co": "const y = x + 1;", "a.co": "const x = 1; var z: int32 = y;"})
+	if len(errs) != 0 {
+		t.Errorf("got errors %q", errs)
+	}
+	wantConsts(t, mod, "x", "1", "y", "2")
+
+	// a clash is reported in the file sorted last
+	_, errs = check(map[string]string{"b.co": "var x: int32;", "a.co": "const x = 1;"})
+	if want := "b.co:1:5: x redeclared in this module (previous declaration at a.co:1:7)"; !slices.Equal(errs, []string{want}) {
+		t.Errorf("got errors %q, want %q", errs, want)
+	}
+}
+
+func TestSuppressWarnings(t *testing.T) {
+	const src = "const a: float32 = 16777217; const f = proc() { const x = 1; };"
+	wantWarnings(t, src, "1:20: constant 16777217 rounded to 1.6777216e+07 in float32", "1:55: x declared but not used")
+
+	if err := base.SuppressWarnings(base.WarnPrecision); err != nil {
+		t.Fatal(err)
+	}
+	defer base.EnableWarnings(base.WarnPrecision)
+	wantWarnings(t, src, "1:55: x declared but not used")
+}
+
+func TestIgnoreDirective(t *testing.T) {
+	// a directive applies to the following line only
+	wantWarnings(t, "// cobalt:ignore precision\nconst a: float32 = 16777217;\nconst b: float32 = 16777217;",
+		"3:20: constant 16777217 rounded to 1.6777216e+07 in float32")
+	wantWarnings(t, "// cobalt:ignore unused,precision\nconst a: float32 = 16777217; const c: float32 = 16777219;")
+	wantWarnings(t, "// cobalt:ignore unused\nconst a: float32 = 16777217;",
+		"2:20: constant 16777217 rounded to 1.6777216e+07 in float32")
+
+	wantErrors(t, "// cobalt:ignore\nconst a = 1;", "1:1: missing warning category in cobalt:ignore")
+	wantErrors(t, "// cobalt:ignore bogus\nconst a = 1;", `1:1: unknown warning category "bogus"`)
+	wantErrors(t, "// cobalt:frobnicate\nconst a = 1;", "1:1: unknown directive cobalt:frobnicate")
+}
+
+func TestResolvedSymbol(t *testing.T) {
+	file, err := syntax.Parse(strings.NewReader("const x = 1; var y: int32 = x; var z = w;"), "test.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nmodules++
+	mod := NewModule("test", fmt.Sprintf("test%d", nmodules))
+	ref := file.DeclList[1].(*syntax.VarDecl).Values.(*syntax.Name)
+	typ := file.DeclList[1].(*syntax.VarDecl).Type.(*syntax.Name)
+	undef := file.DeclList[2].(*syntax.VarDecl).Values.(*syntax.Name)
+	if mod.ResolvedSymbol(ref) != nil {
+		t.Errorf("x resolved before checking")
+	}
+	Check(mod, file)
+
+	if sym := mod.ResolvedSymbol(ref); sym != mod.Lookup("x") {
+		t.Errorf("x resolved to %v, want the constant x", sym)
+	}
+	if sym := mod.ResolvedSymbol(typ); sym == nil || sym.Scope() != Universe {
+		t.Errorf("int32 resolved to %v, want the builtin", sym)
+	}
+	if sym := mod.ResolvedSymbol(undef); sym != nil {
+		t.Errorf("undefined w resolved to %v", sym)
+	}
+
+	// names are resolved per module
+	nmodules++
+	if sym := NewModule("test", fmt.Sprintf("test%d", nmodules)).ResolvedSymbol(ref); sym != nil {
+		t.Errorf("x resolved in another module to %v", sym)
+	}
+}
+
+func TestCheckInModules(t *testing.T) {
+	file, err := syntax.Parse(strings.NewReader("var g: int32; const f = proc(x: int32) int32 { return x + g; };"), "a.co")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := file.DeclList[1].(*syntax.ConstDecl).Values.(*syntax.ProcExpr).Body.StmtList[0].(*syntax.ReturnStmt)
+	ref := ret.Result.(*syntax.Operation).Rhs.(*syntax.Name)
+
+	// the same file checked in two modules is resolved in each separately
+	for range 2 {
+		nmodules++
+		mod := NewModule("test", fmt.Sprintf("test%d", nmodules))
+		if errs := Check(mod, file).Errors; len(errs) != 0 {
+			t.Errorf("got errors %q", errs)
+		}
+		if sym := mod.ResolvedSymbol(ref); sym != mod.Lookup("g") {
+			t.Errorf("g resolved to %v, want the variable g of %s", sym, mod.Path())
+		}
+	}
+}
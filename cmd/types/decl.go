@@ -0,0 +1,399 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/syntax"
+)
+
+// declInfo describes the declaration of a single symbol.
+type declInfo struct {
+	typ    syntax.Expr // type annotation, or nil
+	init   syntax.Expr // initialization expression, or nil
+	const_ bool        // declared with "const"?
+	type_  bool        // declared with "type"? If so, typ is the underlying type
+
+	// for enumerators, the enum type and the previous enumerator, if any
+	enum, prev *Symbol
+
+	// if > 0, init is a single call assigned to this many names
+	ncall int
+}
+
+// collectDecls declares all global symbols in the module scope, such that
+// globals may be referenced regardless of the order of declaration. The
+// symbols themselves are checked later on, by symDecl.
+func (check *checker) collectDecls(list []syntax.Decl) {
+	for _, d := range list {
+		switch d := d.(type) {
+		case *syntax.ConstDecl:
+			check.collectSyms(d.NameList, d.Type, d.Values, true)
+		case *syntax.VarDecl:
+			check.collectSyms(d.NameList, d.Type, d.Values, false)
+		case *syntax.TypeDecl:
+			sym := check.newSymbol(d.Name, true)
+			check.collect(sym, &declInfo{typ: d.Type, const_: true, type_: true})
+		case *syntax.EnumDecl:
+			enum := check.newEnum(d.Name)
+			check.collect(enum, nil)
+			var prev *Symbol
+			for _, e := range d.List {
+				sym := check.newSymbol(e.Name, true)
+				check.collect(sym, &declInfo{init: e.Value, const_: true, enum: enum, prev: prev})
+				prev = sym
+			}
+		default:
+			check.errorf(d.Pos(), "unexpected declaration %T", d)
+		}
+	}
+}
+
+func (check *checker) collectSyms(names []*syntax.Name, typ, values syntax.Expr, const_ bool) {
+	inits, ncall := check.pairInits(names, values)
+	for i, name := range names {
+		sym := check.newSymbol(name, const_)
+		check.collect(sym, &declInfo{typ: typ, init: inits[i], const_: const_, ncall: ncall})
+	}
+}
+
+// collect declares the global symbol sym in the module scope. Unless d is nil,
+// sym is checked later on according to d.
+func (check *checker) collect(sym *Symbol, d *declInfo) {
+	if sym.name == "_" {
+		// blank symbols are checked, but never declared
+	} else if alt := check.mod.Insert(sym); alt != nil {
+		check.errorf(sym.pos, "%s redeclared in this module (previous declaration at %s)", sym.name, alt.pos)
+		return
+	}
+	check.order = append(check.order, sym)
+	if d != nil {
+		check.decls[sym] = d
+	}
+}
+
+// pairInits pairs each name with its initialization expression. If values is
+// nil, all names are paired with a nil expression.
+//
+// If values is a single call for multiple names, the mismatch can only be
+// reported once the call is checked. In that case, the call is paired with
+// the first name only, and ncall is the number of names.
+func (check *checker) pairInits(names []*syntax.Name, values syntax.Expr) (inits []syntax.Expr, ncall int) {
+	inits = make([]syntax.Expr, len(names))
+	if values == nil {
+		return inits, 0
+	}
+
+	list := syntax.UnpackList(values)
+	if len(list) != len(names) {
+		if _, ok := values.(*syntax.CallExpr); ok {
+			inits[0] = values
+			return inits, len(names)
+		}
+		check.assignError(list, len(names), len(list))
+	}
+	copy(inits, list)
+	return inits, 0
+}
+
+func (check *checker) newSymbol(name *syntax.Name, const_ bool) *Symbol {
+	sym := &Symbol{name: name.Value, pos: name.Pos(), mod: check.mod}
+	if const_ {
+		sym.flags |= symConst
+	}
+	return sym
+}
+
+// symDecl checks the declaration of a global symbol, if it was not checked
+// already. As globals may be referenced before they are declared, symDecl is
+// called whenever an unchecked global symbol is encountered.
+func (check *checker) symDecl(sym *Symbol) {
+	if sym.typ != nil {
+		return // already checked
+	}
+
+	if sym.flags&symChecking != 0 {
+		check.errorf(sym.pos, "initialization cycle: %s refers to itself", sym.name)
+		sym.typ = Types[TUNDEF]
+		return
+	}
+
+	d := check.decls[sym]
+	if d == nil {
+		return // not a global symbol, or not declared by this file
+	}
+
+	// globals are always checked in the module scope
+	defer func(scope *Scope, proc *Proc) {
+		check.scope, check.proc = scope, proc
+	}(check.scope, check.proc)
+	check.scope, check.proc = check.mod.scope, nil
+
+	sym.flags |= symChecking
+	if d.type_ {
+		check.typeDecl(sym, d.typ)
+	} else {
+		check.initSym(sym, d)
+	}
+	sym.flags &^= symChecking
+}
+
+// typeDecl declares sym as a new named type, with the underlying type denoted
+// by e. The named type is set up before e is checked, such that it may refer
+// to itself, e.g. through a pointer.
+func (check *checker) typeDecl(sym *Symbol, e syntax.Expr) {
+	named := &Type{sym: sym}
+	sym.typ = Types[TTYPE]
+	sym.extra = MakeType(named)
+	sym.flags |= symStatic | symChecking
+	defer func() { sym.flags &^= symChecking }()
+
+	under := check.typ(e)
+	if under.sym != nil && under.sym.flags&symChecking != 0 {
+		// under is a named type whose declaration is still being checked
+		check.errorf(e.Pos(), "invalid recursive type %s", sym.name)
+		under = Types[TUNDEF]
+	}
+	named.kind, named.extra = under.kind, under.extra
+
+	if containsItself(named) {
+		// the type would be of infinite size; break the cycle so that
+		// computing sizes terminates
+		check.errorf(sym.pos, "invalid recursive type %s", sym.name)
+		named.kind, named.extra = TUNDEF, nil
+	}
+}
+
+// containsItself reports whether the named type t contains a value of type t,
+// directly or through other types. Pointers and procedures break such cycles,
+// as they refer to values rather than containing them.
+func containsItself(t *Type) bool {
+	return contains(t, t, make(map[*Type]bool))
+}
+
+// contains reports whether a value of type u contains a value of type t. Named
+// types whose declarations are still being checked are skipped, as they are
+// checked once their declaration is complete.
+func contains(u, t *Type, seen map[*Type]bool) bool {
+	var elems []*Type
+	switch u.kind {
+	case TARRAY, TOPTION:
+		elems = []*Type{u.Elem()}
+	case TSTRUCT:
+		for _, f := range u.extra.(*Struct).Fields {
+			elems = append(elems, f.Type)
+		}
+	}
+
+	for _, e := range elems {
+		if e == t {
+			return true
+		}
+		if seen[e] || e.sym != nil && e.sym.flags&symChecking != 0 {
+			continue
+		}
+		seen[e] = true
+		if contains(e, t, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// newEnum returns the symbol declaring the named type of an enum. As the type
+// does not depend on the enumerators, it is complete right away.
+func (check *checker) newEnum(name *syntax.Name) *Symbol {
+	sym := check.newSymbol(name, true)
+	sym.typ = Types[TTYPE]
+	sym.extra = MakeType(&Type{kind: TINT32, sym: sym})
+	sym.flags |= symStatic
+	return sym
+}
+
+// enumerator determines the value of the enumerator sym. Without an explicit
+// value, it is the value of the previous enumerator plus one, or zero for the
+// first enumerator.
+func (check *checker) enumerator(sym *Symbol, d *declInfo) {
+	T := d.enum.extra.(typeValue).t
+
+	var x operand
+	if d.init != nil {
+		check.expr(&x, d.init)
+		if x.mode == invalid {
+			sym.typ = Types[TUNDEF]
+			return
+		}
+		if x.mode != constant || !isIntegral(x.typ) {
+			check.errorf(d.init.Pos(), "enumerator value %s must be a constant integer", &x)
+			sym.typ = Types[TUNDEF]
+			return
+		}
+	} else {
+		val := MakeInt(0)
+		if d.prev != nil {
+			check.symDecl(d.prev)
+			if !isValid(d.prev.typ) {
+				sym.typ = Types[TUNDEF] // error reported elsewhere
+				return
+			}
+			val = d.prev.extra.(Value).Convert(TUNTYPEDINT).Binary(syntax.Add, MakeInt(1))
+		}
+		x.mode = constant
+		x.expr = syntax.Synthesized(sym.pos, &syntax.Name{Value: sym.name})
+		x.typ = Types[TUNTYPEDINT]
+		x.val = val
+	}
+
+	check.assignment(&x, T, "enumerator")
+	if x.mode == invalid {
+		sym.typ = Types[TUNDEF]
+		return
+	}
+
+	sym.typ = T
+	sym.flags |= symStatic
+	sym.extra = x.val
+}
+
+// initSym determines the type and, if any, the static value of sym, based on
+// its declaration.
+func (check *checker) initSym(sym *Symbol, d *declInfo) {
+	if d.enum != nil {
+		check.enumerator(sym, d)
+		return
+	}
+
+	var T *Type
+	if d.typ != nil {
+		T = check.varType(d.typ)
+	}
+
+	if d.ncall > 0 && d.init != nil {
+		// a single call cannot initialize multiple names
+		var x operand
+		check.rawExpr(&x, d.init, nil)
+		if x.mode != invalid {
+			check.assignError([]syntax.Expr{d.init}, d.ncall, resultCount(&x))
+		}
+		sym.typ = Types[TUNDEF]
+		return
+	}
+
+	if d.init == nil {
+		// the parser guarantees a type annotation if there is no
+		// initialization expression, but mismatched names may lack both
+		if T == nil {
+			T = Types[TUNDEF]
+		}
+		sym.typ = T
+		return
+	}
+
+	var x operand
+	if d.const_ {
+		// constants may be types
+		check.rawExpr(&x, d.init, T)
+		check.exclude(&x, 1<<novalue|1<<builtin)
+	} else {
+		check.exprWithHint(&x, d.init, T)
+	}
+
+	if x.mode == typexpr {
+		if T != nil && T.kind != TTYPE {
+			check.errorf(d.init.Pos(), "cannot use %s as %s value in declaration", &x, T)
+		}
+		sym.typ = Types[TTYPE]
+		sym.extra = MakeType(x.typ)
+		sym.flags |= symStatic
+		return
+	}
+
+	if T != nil {
+		check.assignment(&x, T, "declaration")
+	} else if x.mode != invalid {
+		// only constants may remain untyped
+		if x.mode != constant || !d.const_ {
+			check.defaultType(&x)
+		}
+		T = x.typ
+	}
+
+	if x.mode == invalid {
+		sym.typ = Types[TUNDEF]
+		return
+	}
+
+	sym.typ = T
+	if x.mode == constant {
+		sym.flags |= symStatic
+		sym.extra = x.val
+	} else if e, ok := d.init.(*syntax.ProcExpr); ok && d.const_ {
+		// constant procedures are bound to their literal, see recordCall
+		sym.extra = check.mod.procs[e]
+	}
+}
+
+// declStmt checks a local declaration and declares its symbols in the current
+// scope. The symbols are declared after checking all initialization
+// expressions, such that they cannot refer to the symbols being declared.
+func (check *checker) declStmt(d syntax.Decl) {
+	var names []*syntax.Name
+	var typ, values syntax.Expr
+	var const_ bool
+
+	switch d := d.(type) {
+	case *syntax.ConstDecl:
+		names, typ, values, const_ = d.NameList, d.Type, d.Values, true
+	case *syntax.VarDecl:
+		names, typ, values = d.NameList, d.Type, d.Values
+	case *syntax.TypeDecl:
+		// the type is declared first, such that it may refer to itself
+		sym := check.newSymbol(d.Name, true)
+		check.declare(sym)
+		check.typeDecl(sym, d.Type)
+		return
+	case *syntax.EnumDecl:
+		// enumerators may refer to the preceding enumerators
+		enum := check.newEnum(d.Name)
+		check.declare(enum)
+		var prev *Symbol
+		for _, e := range d.List {
+			sym := check.newSymbol(e.Name, true)
+			check.initSym(sym, &declInfo{init: e.Value, const_: true, enum: enum, prev: prev})
+			check.declare(sym)
+			prev = sym
+		}
+		return
+	default:
+		check.errorf(d.Pos(), "unexpected declaration %T", d)
+		return
+	}
+
+	inits, ncall := check.pairInits(names, values)
+	syms := make([]*Symbol, len(names))
+	for i, name := range names {
+		syms[i] = check.newSymbol(name, const_)
+		check.initSym(syms[i], &declInfo{typ: typ, init: inits[i], const_: const_, ncall: ncall})
+	}
+
+	for _, sym := range syms {
+		check.declare(sym)
+	}
+}
+
+// declare declares sym in the current scope. Blank symbols are not declared.
+func (check *checker) declare(sym *Symbol) {
+	if sym.name == "_" {
+		return
+	}
+	if alt := check.scope.Insert(sym); alt != nil {
+		check.errorf(sym.pos, "%s redeclared in this block (previous declaration at %s)", sym.name, alt.pos)
+		return
+	}
+	// built-in symbols have no position, and are shadowed deliberately
+	if alt, ok := check.scope.Shadows(sym.name); ok && alt.pos.Known() {
+		check.warnf(sym.pos, base.WarnShadow, "declaration of %s shadows declaration at %s", sym.name, alt.pos)
+	}
+}
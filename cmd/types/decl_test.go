@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGlobalDecls(t *testing.T) {
+	// globals may be used before their declaration
+	mod := wantErrors(t, "var a: int32 = b; var b: int32 = 1; const c = d * 2; const d = 3;")
+	wantConsts(t, mod, "c", "6", "d", "3")
+	if got := mod.Lookup("a").typ.String(); got != "int32" {
+		t.Errorf("a has type %s, want int32", got)
+	}
+
+	wantErrors(t, "const x = 1; var x: int32;", "1:18: x redeclared in this module (previous declaration at test.co:1:7)")
+	wantErrors(t, "const a = a + 1;", "1:7: initialization cycle: a refers to itself")
+	wantErrors(t, "var a: undefinedT;", "1:8: undefined: undefinedT")
+	wantErrors(t, "var a = int32;", "1:9: int32 (type int32) is not an expression")
+}
+
+func TestDeclTypes(t *testing.T) {
+	wantErrors(t, "var a: [3]?*int32; var b: struct{x: int32; y: [2]bool;}; var c: proc(int32) bool;")
+	wantErrors(t, "var a: struct{x: int32; x: int8;};", "1:25: duplicate field x")
+	wantErrors(t, "var a: [3]int32; var b: [a]int32;", "1:26: array length a (variable of type [3]int32) must be a constant integer")
+
+	// constants must be representable in the declared type
+	wantErrors(t, "var a: int8 = 300; var b: int32 = 1.5;", "1:15: constant 300 overflows int8", "1:35: constant 1.5 truncated to int32")
+}
+
+func TestLocalDecls(t *testing.T) {
+	wantErrors(t, "const f = proc(x: int32, x: int32) {};", "1:26: duplicate parameter x")
+	wantErrors(t, "var g: bool; const f = proc() { var x: int32; { var x: bool; g = x; } g = x == 1; };")
+	wantErrors(t, "const f = proc() { var x: int32 = 1; var x: int32; };",
+		"1:42: x redeclared in this block (previous declaration at test.co:1:24)")
+	wantErrors(t, "const f = proc() { x = 1; };", "1:20: undefined: x")
+}
+
+func TestVoidValueType(t *testing.T) {
+	wantErrors(t, "var x: void;", "1:8: void is not a value type")
+	wantErrors(t, "var a: [3]void;", "1:11: void is not a value type")
+	wantErrors(t, "var s: struct{f: void;};", "1:18: void is not a value type")
+	wantErrors(t, "const f = proc() void {}; var g: proc() void;")
+
+	wantErrors(t, "var a: ?void;", "1:9: void is not a value type")
+	wantErrors(t, "var f: proc(x: void);", "1:16: void is not a value type")
+	wantErrors(t, "const f = proc() { var x: void; };", "1:27: void is not a value type", "1:24: x declared but not used")
+	wantErrors(t, "const f = proc() void { return; }; var p: *void;")
+}
+
+func TestEnumeratorPos(t *testing.T) {
+	// an enumerator without a value is checked as a synthesized name
+	wantErrors(t, "enum E { A = 2147483647, B }", "1:26: constant 2147483648 overflows E")
+}
+
+func TestHigherOrderProc(t *testing.T) {
+	mod := wantErrors(t, "var f: proc() proc(int32) int32; const g = proc(x: int32) int32 { return x; }; const h = proc() proc(int32) int32 { return g; }; var y: int32 = f()(1) + h()(2);")
+	T := mod.Lookup("f").typ
+	if got, want := T.String(), "proc() proc(int32) int32"; got != want {
+		t.Errorf("got type %s, want %s", got, want)
+	}
+	res := T.extra.(*Signature).Result
+	if res.kind != TPROC || res.String() != "proc(int32) int32" {
+		t.Errorf("got result type %s, want proc(int32) int32", res)
+	}
+
+	wantErrors(t, "const g = proc() int32 { return 1; }; var f: proc() proc(int32) int32 = g;",
+		"1:73: cannot use g (value of type proc() int32) as proc() proc(int32) int32 value in declaration")
+}
+
+func TestNamedType(t *testing.T) {
+	mod := wantErrors(t, "type Celsius int32; var c: Celsius; var p: *Celsius; var a: [2]Celsius;")
+	sym := mod.Lookup("Celsius")
+	if sym.typ.kind != TTYPE {
+		t.Fatalf("Celsius has type %s, want a type of types", sym.typ)
+	}
+	T := sym.extra.(typeValue).t
+	if T.Sym() != sym || T.kind != TINT32 {
+		t.Errorf("Celsius denotes %s, want named int32", T)
+	}
+	for _, test := range []struct{ name, want string }{
+		{"c", "Celsius"},
+		{"p", "*Celsius"},
+		{"a", "[2]Celsius"},
+	} {
+		if got := mod.Lookup(test.name).typ.String(); got != test.want {
+			t.Errorf("%s has type %s, want %s", test.name, got, test.want)
+		}
+	}
+
+	wantErrors(t, "type Celsius int32; var c: Celsius = (int32)1;",
+		"1:38: cannot use constant 1 of type int32 as Celsius value in declaration")
+}
+
+func TestEnumValues(t *testing.T) {
+	mod := wantErrors(t, "enum Color { Red, Green = 5, Blue } const c: Color = Blue; const n = (int32)Blue;")
+	wantConsts(t, mod, "Red", "0", "Green", "5", "Blue", "6", "n", "6")
+	for _, name := range []string{"Red", "Green", "Blue"} {
+		sym := mod.Lookup(name)
+		if sym.flags&(symConst|symStatic) != symConst|symStatic {
+			t.Errorf("%s is not a constant symbol", name)
+		}
+		if got := sym.typ.String(); got != "Color" {
+			t.Errorf("%s has type %s, want Color", name, got)
+		}
+	}
+
+	mod = wantErrors(t, "enum E { A = -2, B, C = B + 10, D }")
+	wantConsts(t, mod, "A", "-2", "B", "-1", "C", "9", "D", "10")
+
+	wantErrors(t, "enum E { A = 1.5 }", "1:14: enumerator value untyped float constant 1.5 must be a constant integer")
+}
+
+func TestBlankIdentifier(t *testing.T) {
+	mod := wantErrors(t, "var _ = 5; var _: int32; const _ = 1; var _, _ = 1, 2; "+
+		"const f = proc() { var x: int32; _ = x + 1; var _ = x; _, _ = 1, 2; _ = true; _ = f; };")
+	if sym := mod.Lookup("_"); sym != nil {
+		t.Errorf("_ is declared as %v, want no symbol", sym)
+	}
+
+	wantErrors(t, "var x = _;", "1:9: cannot use _ as value")
+	wantErrors(t, "const f = proc() { _ += 1; _++; };", "1:20: cannot use _ as value", "1:28: cannot use _ as value")
+	wantErrors(t, "const f = proc() { _ = none; };", "1:24: none used without an option type")
+}
+
+func TestProcParams(t *testing.T) {
+	mod := wantErrors(t, "const f = proc(a: int32, b: bool) int32 { return a; };")
+	p := mod.Lookup("f").extra.(*Proc)
+	var got []string
+	for _, sym := range p.params {
+		got = append(got, sym.name+" "+sym.typ.String())
+		if p.body.Lookup(sym.name) != sym {
+			t.Errorf("parameter %s not declared in the body scope", sym.name)
+		}
+	}
+	if want := []string{"a int32", "b bool"}; !slices.Equal(got, want) {
+		t.Errorf("got parameters %q, want %q", got, want)
+	}
+
+	// only procedure types may leave parameters unnamed
+	wantErrors(t, "var t: proc(int32, bool) void; const g = proc(_: int32, _: int32) {};")
+	wantErrors(t, "const g = proc(int32) {};", "1:16: missing parameter name")
+	wantErrors(t, "const h = proc(a: int32, a: int32) {};", "1:26: duplicate parameter a")
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	// a type cannot contain itself by value
+	wantErrors(t, "type S struct{s: S;};", "1:6: invalid recursive type S")
+	wantErrors(t, "type A struct{b: B;}; type B struct{a: [2]A;};", "1:6: invalid recursive type A")
+	wantErrors(t, "type O struct{o: ?O;};", "1:6: invalid recursive type O")
+
+	// pointers and procedures break cycles
+	wantErrors(t, "type A struct{b: *B;}; type B struct{a: A;}; type L struct{next: ?*L; v: int32;}; var x: A; var y: L;")
+	wantErrors(t, "type P *P; type F proc(F) F;")
+}
+
+func TestVoidResult(t *testing.T) {
+	// an omitted result is normalized to void, such that it is never nil
+	mod := wantErrors(t, "var f: proc(); var g: proc() void; const h = proc() {}; var k: proc(int32);")
+	for _, name := range []string{"f", "g", "h", "k"} {
+		res := mod.Lookup(name).typ.extra.(*Signature).Result
+		if res == nil || res.Kind() != TVOID {
+			t.Errorf("%s has result %v, want void", name, res)
+		}
+	}
+	if res := NewSignature(nil, nil).extra.(*Signature).Result; res != Types[TVOID] {
+		t.Errorf("NewSignature(nil, nil) has result %v, want void", res)
+	}
+}
@@ -0,0 +1,963 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the type-checking of expressions, including the
+// evaluation of constant expressions.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/src"
+	"cobalt/syntax"
+	"math"
+	"slices"
+)
+
+// expr type-checks the expression e and initializes x with its value.
+// The result must be a single value.
+func (check *checker) expr(x *operand, e syntax.Expr) {
+	check.exprWithHint(x, e, nil)
+}
+
+// exprWithHint is like expr, but uses hint as the type of compound literals
+// without an explicit type. The hint may be nil.
+func (check *checker) exprWithHint(x *operand, e syntax.Expr, hint *Type) {
+	check.rawExpr(x, e, hint)
+	check.exclude(x, 1<<novalue|1<<builtin|1<<typexpr)
+}
+
+// exclude reports an error if x.mode is in modeset and sets x.mode to invalid.
+// The modeset may contain any of 1<<novalue, 1<<builtin and 1<<typexpr.
+func (check *checker) exclude(x *operand, modeset uint) {
+	if modeset&(1<<x.mode) == 0 {
+		return
+	}
+
+	var msg string
+	switch x.mode {
+	case novalue:
+		msg = "%s used as value"
+	case builtin:
+		msg = "%s must be called"
+	case typexpr:
+		msg = "%s is not an expression"
+	default:
+		base.Fatalf("types: unexpected operand mode %d", x.mode)
+	}
+	check.errorf(x.expr.Pos(), msg, x)
+	x.mode = invalid
+}
+
+// rawExpr type-checks the expression e and initializes x with its value or
+// type. If hint is not nil, it is used as the type of compound literals.
+func (check *checker) rawExpr(x *operand, e syntax.Expr, hint *Type) {
+	x.mode = invalid
+	x.typ = nil
+	x.val = nil
+	check.exprInternal(x, e, hint)
+	x.expr = e
+}
+
+func (check *checker) exprInternal(x *operand, e syntax.Expr, hint *Type) {
+	switch e := e.(type) {
+	case *syntax.Name:
+		check.ident(x, e)
+
+	case *syntax.LiteralExpr:
+		if e.Kind == syntax.String {
+			check.errorf(e.Pos(), "string literals are not supported")
+			return
+		}
+		val := LiteralValue(e.Kind, e.Value)
+		if val == Undefined {
+			check.errorf(e.Pos(), "%s literal %s out of range", kindName(e.Kind), e.Value)
+			return
+		}
+		x.mode = constant
+		x.typ = Types[val.Kind()]
+		x.val = val
+
+	case *syntax.NoneExpr:
+		check.none(x, e, hint)
+
+	case *syntax.CompoundExpr:
+		check.compound(x, e, hint)
+
+	case *syntax.ProcExpr:
+		check.procExpr(x, e)
+
+	case *syntax.Operation:
+		switch {
+		case e.Op == syntax.Inc || e.Op == syntax.Dec:
+			// x++ and x-- are statements, but the operand is still checked
+			check.errorf(e.Pos(), "%s used in expression context", incDecName(e.Op))
+			y := e.Lhs // x++, x--
+			if y == nil {
+				y = e.Rhs // ++x, --x
+			}
+			check.incDec(e.Pos(), e.Op, y)
+		case e.Lhs == nil:
+			check.unary(x, e, e.Rhs) // prefix
+		case e.Rhs == nil:
+			check.unary(x, e, e.Lhs) // postfix
+		default:
+			check.binary(x, e)
+		}
+
+	case *syntax.TernaryExpr:
+		check.ternary(x, e, hint)
+
+	case *syntax.CallExpr:
+		check.call(x, e)
+
+	case *syntax.CastExpr:
+		check.cast(x, e)
+
+	case *syntax.IndexExpr:
+		check.index(x, e)
+
+	case *syntax.SelectorExpr:
+		check.selector(x, e)
+
+	case *syntax.ListExpr:
+		check.errorf(e.Pos(), "unexpected list of expressions")
+
+	case *syntax.AssignExpr:
+		check.errorf(e.Pos(), "unexpected assignment outside of compound literal")
+
+	case *syntax.PointerType, *syntax.OptionType, *syntax.ArrayType, *syntax.ProcType, *syntax.StructType:
+		x.mode = typexpr
+		x.typ = check.typExpr(e)
+
+	default:
+		base.Fatalf("types: unexpected expression %T", e)
+	}
+}
+
+func kindName(kind syntax.Literal) string {
+	switch kind {
+	case syntax.Int:
+		return "integer"
+	case syntax.Float:
+		return "floating-point"
+	}
+	return "character"
+}
+
+// ident type-checks a name and initializes x with the referenced symbol.
+func (check *checker) ident(x *operand, e *syntax.Name) {
+	if e.Value == "_" {
+		check.errorf(e.Pos(), "cannot use _ as value")
+		return
+	}
+	_, sym := check.scope.LookupParent(e.Value)
+	if sym == nil {
+		check.errorf(e.Pos(), "undefined: %s", e.Value)
+		return
+	}
+	check.mod.names[e] = sym
+	sym.flags |= symUsed
+	check.symDecl(sym)
+
+	if sym.flags&symBuiltin != 0 {
+		x.mode = builtin
+		x.id = sym.extra.(Builtin)
+		return
+	}
+
+	if t, ok := sym.extra.(typeValue); ok && sym.flags&symStatic != 0 {
+		x.mode = typexpr
+		x.typ = t.t
+		return
+	}
+
+	if !isValid(sym.typ) {
+		return // error reported elsewhere
+	}
+
+	switch {
+	case sym.flags&(symConst|symStatic) == symConst|symStatic:
+		x.mode = constant
+		x.val = sym.extra.(Value)
+	case sym.flags&symConst != 0:
+		x.mode = value
+	default:
+		x.mode = variable
+	}
+	x.typ = sym.typ
+}
+
+// unary type-checks the unary operation e with the operand y.
+func (check *checker) unary(x *operand, e *syntax.Operation, y syntax.Expr) {
+	check.expr(x, y)
+	if x.mode == invalid {
+		return
+	}
+
+	switch e.Op {
+	case syntax.And: // &x
+		if x.mode == variable {
+			x.mode = value
+			x.typ = NewPointer(x.typ, false)
+			return
+		}
+		if name, ok := y.(*syntax.Name); ok {
+			// taking the address of a constant yields a pointer-to-const
+			if sym := check.mod.names[name]; sym != nil && sym.flags&symConst != 0 {
+				check.defaultType(x)
+				x.mode = value
+				x.typ = NewPointer(x.typ, true)
+				return
+			}
+		}
+		check.errorf(e.Pos(), "cannot take address of %s", x)
+		x.mode = invalid
+		return
+
+	case syntax.Deref: // x.*
+		if !isPointer(x.typ) {
+			check.errorf(e.Pos(), "invalid operation: cannot dereference %s", x)
+			x.mode = invalid
+			return
+		}
+		// the pointee of a pointer-to-const cannot be written
+		x.mode = variable
+		if x.typ.extra.(*Pointer).Const {
+			x.mode = value
+		}
+		x.typ = x.typ.Elem()
+		return
+
+	}
+
+	var ok bool
+	switch e.Op {
+	case syntax.Add, syntax.Sub:
+		ok = isNumeric(x.typ)
+	case syntax.Not:
+		ok = isIntegral(x.typ)
+	case syntax.LNot:
+		ok = isBoolean(x.typ)
+	}
+	if !ok {
+		check.errorf(e.Pos(), "invalid operation: operator %s not defined on %s", e.Op.Symbol(), x)
+		x.mode = invalid
+		return
+	}
+
+	if x.mode == constant {
+		val := x.val.Unary(e.Op)
+		if val == Undefined {
+			check.errorf(e.Pos(), "constant %s%s overflows", e.Op.Symbol(), x.val)
+			x.mode = invalid
+			return
+		}
+		if e.Op == syntax.Not && !isUntyped(x.typ) {
+			// ~x is computed within the bits of its type
+			val = val.Convert(valueKind(x.typ.kind))
+		}
+		check.constResult(x, val, e)
+		return
+	}
+
+	x.mode = value
+}
+
+// constResult sets the value of the constant x to the result val of the
+// operation e. Results of operations on untyped constants remain untyped, but
+// those on typed constants must be representable by the type of x.
+func (check *checker) constResult(x *operand, val Value, e syntax.Expr) {
+	x.val = val
+	x.expr = e
+	if isUntyped(x.typ) {
+		x.typ = Types[val.Kind()]
+		return
+	}
+	check.representable(x, x.typ)
+}
+
+// incDec type-checks the increment or decrement op of y at pos, which may only
+// be used as a statement. The operand must be a numeric variable.
+func (check *checker) incDec(pos src.Pos, op syntax.Operator, y syntax.Expr) {
+	var x operand
+	check.expr(&x, y)
+	if x.mode == invalid {
+		return
+	}
+
+	if !isNumeric(x.typ) {
+		check.errorf(pos, "invalid operation: %s%s (non-numeric type %s)", op.Symbol(), &x, x.typ)
+		return
+	}
+	if x.mode != variable {
+		if isDeref(y) {
+			check.errorf(pos, "invalid operation: cannot %s through const pointer", incDecName(op))
+			return
+		}
+		if sym := check.constSym(y); sym != nil {
+			check.errorf(pos, "invalid operation: cannot %s constant %s", incDecName(op), sym.name)
+			return
+		}
+		check.errorf(pos, "invalid operation: cannot %s %s", incDecName(op), &x)
+		return
+	}
+	check.recordWrite(y)
+}
+
+func incDecName(op syntax.Operator) string {
+	if op == syntax.Inc {
+		return "increment"
+	}
+	return "decrement"
+}
+
+// binary type-checks the binary operation e.
+func (check *checker) binary(x *operand, e *syntax.Operation) {
+	var y operand
+	switch {
+	case isNone(e.Lhs):
+		// none takes the type of the other operand
+		check.expr(&y, e.Rhs)
+		check.exprWithHint(x, e.Lhs, noneType(&y))
+	case isNone(e.Rhs):
+		check.expr(x, e.Lhs)
+		check.exprWithHint(&y, e.Rhs, noneType(x))
+	default:
+		check.expr(x, e.Lhs)
+		check.expr(&y, e.Rhs)
+	}
+	if x.mode == invalid {
+		return
+	}
+	if y.mode == invalid {
+		x.mode = invalid
+		return
+	}
+
+	op := e.Op
+	if op == syntax.Shl || op == syntax.Shr {
+		check.shift(x, &y, e)
+		return
+	}
+
+	// pointer arithmetic: the pointer must be on the left-hand side
+	if (op == syntax.Add || op == syntax.Sub) && isPointer(x.typ) {
+		if !isIntegral(y.typ) {
+			check.errorf(e.Pos(), "invalid operation: pointer arithmetic requires an integral operand, got %s", &y)
+			x.mode = invalid
+			return
+		}
+		x.mode = value
+		return
+	}
+
+	check.matchTypes(x, &y)
+	if x.mode == invalid {
+		return
+	}
+
+	if op.IsComparison() {
+		check.comparison(x, &y, e)
+		return
+	}
+
+	// untyped operands may mix, such as in 1 + 2.5
+	if !Identical(x.typ, y.typ) && !(isUntyped(x.typ) && isUntyped(y.typ)) {
+		if isValid(x.typ) && isValid(y.typ) {
+			check.errorf(e.Pos(), "invalid operation: %s %s %s (mismatched types)", x.typ, op.Symbol(), y.typ)
+		}
+		x.mode = invalid
+		return
+	}
+
+	for _, z := range []*operand{x, &y} {
+		if !binaryOpAllowed(op, z.typ) {
+			check.errorf(e.Pos(), "invalid operation: operator %s not defined on %s", op.Symbol(), z)
+			x.mode = invalid
+			return
+		}
+	}
+
+	// division by zero is only defined at run time for floating-point values
+	if (op == syntax.Div || op == syntax.Rem) && y.mode == constant && (x.mode == constant || isIntegral(x.typ)) {
+		if boolVal(y.val.Binary(syntax.Eql, MakeInt(0))) {
+			check.errorf(e.Pos(), "invalid operation: division by zero")
+			x.mode = invalid
+			return
+		}
+	}
+
+	if x.mode == constant && y.mode == constant {
+		val := x.val.Binary(op, y.val)
+		if val == Undefined {
+			check.errorf(e.Pos(), "invalid constant operation: %s %s %s", x.val, op.Symbol(), y.val)
+			x.mode = invalid
+			return
+		}
+		check.constResult(x, val, e)
+		return
+	}
+
+	x.mode = value
+}
+
+func binaryOpAllowed(op syntax.Operator, t *Type) bool {
+	switch op {
+	case syntax.Add, syntax.Sub, syntax.Mul, syntax.Div:
+		return isNumeric(t)
+	case syntax.Rem, syntax.Or, syntax.Xor, syntax.And:
+		return isIntegral(t)
+	case syntax.OrOr, syntax.AndAnd:
+		return isBoolean(t)
+	}
+	return false
+}
+
+// matchTypes attempts to convert an untyped operand to the type of the other,
+// typed operand, such that both operands have identical types.
+func (check *checker) matchTypes(x, y *operand) {
+	switch xu, yu := isUntyped(x.typ), isUntyped(y.typ); {
+	case xu && !yu:
+		check.implicitType(x, y.typ)
+	case yu && !xu:
+		check.implicitType(y, x.typ)
+		if y.mode == invalid {
+			x.mode = invalid
+		}
+	}
+}
+
+// comparison type-checks the comparison e, with x and y as its operands.
+func (check *checker) comparison(x, y *operand, e *syntax.Operation) {
+	op := e.Op
+	ordered := op != syntax.Eql && op != syntax.Neq
+
+	var ok bool
+	switch {
+	case isUntyped(x.typ) && isUntyped(y.typ):
+		ok = isNumeric(x.typ) && isNumeric(y.typ) || !ordered && isBoolean(x.typ) && isBoolean(y.typ)
+	case isPointer(x.typ) && isPointer(y.typ) && !Identical(x.typ, y.typ):
+		// pointers differing only in the const-ness of their element
+		// point to the same kind of object
+		if !AssignableTo(x.typ, y.typ) && !AssignableTo(y.typ, x.typ) {
+			check.errorf(e.Pos(), "invalid operation: mismatched pointer types in comparison (%s and %s)", x.typ, y.typ)
+			x.mode = invalid
+			return
+		}
+		ok = !ordered
+	case !Identical(x.typ, y.typ):
+		if isValid(x.typ) && isValid(y.typ) {
+			check.errorf(e.Pos(), "invalid operation: %s %s %s (mismatched types)", x.typ, op.Symbol(), y.typ)
+		}
+		x.mode = invalid
+		return
+	case ordered:
+		ok = x.typ.kind.IsOrdered()
+	default:
+		ok = comparable(x.typ)
+	}
+
+	if !ok {
+		check.errorf(e.Pos(), "invalid operation: operator %s not defined on %s", op.Symbol(), x)
+		x.mode = invalid
+		return
+	}
+
+	// comparisons always yield an untyped boolean
+	if x.mode == constant && y.mode == constant {
+		x.val = x.val.Binary(op, y.val)
+		x.typ = Types[TUNTYPEDBOOL]
+		return
+	}
+
+	x.mode = value
+	x.typ = Types[TUNTYPEDBOOL]
+}
+
+// shift type-checks the shift operation e, with x and y as its operands.
+func (check *checker) shift(x, y *operand, e *syntax.Operation) {
+	if !isIntegral(x.typ) {
+		check.errorf(e.Pos(), "invalid operation: shifted operand %s must be integral", x)
+		x.mode = invalid
+		return
+	}
+	if !isIntegral(y.typ) {
+		check.errorf(e.Pos(), "invalid operation: %s %s %s (shift count must be integral)", x.typ, e.Op.Symbol(), y.typ)
+		x.mode = invalid
+		return
+	}
+
+	if y.mode == constant {
+		if n, ok := int64Val(y.val); ok && n < 0 {
+			check.errorf(e.Pos(), "invalid operation: negative shift count %s", y.val)
+			x.mode = invalid
+			return
+		}
+		if x.mode == constant {
+			val := x.val.Binary(e.Op, y.val)
+			if val == Undefined && !isUntyped(x.typ) {
+				// typed constants cannot be shifted by their width or more
+				check.errorf(e.Pos(), "invalid operation: shift count %s too large for %s", y.val, x.typ)
+				x.mode = invalid
+				return
+			}
+			if val == Undefined {
+				check.errorf(e.Pos(), "invalid constant operation: %s %s %s", x.val, e.Op.Symbol(), y.val)
+				x.mode = invalid
+				return
+			}
+			check.constResult(x, val, e)
+			return
+		}
+	}
+
+	// a non-constant shift of an untyped constant has the default type
+	check.defaultType(x)
+	if x.mode == invalid {
+		return
+	}
+	x.mode = value
+}
+
+// cond type-checks a condition. Conditions may be boolean or optional values.
+func (check *checker) cond(x *operand, e syntax.Expr) {
+	check.expr(x, e)
+	if x.mode != invalid && !isBoolean(x.typ) && x.typ.kind != TOPTION {
+		check.errorf(e.Pos(), "non-boolean condition %s", x)
+		x.mode = invalid
+	}
+}
+
+func (check *checker) ternary(x *operand, e *syntax.TernaryExpr, hint *Type) {
+	var cond, y operand
+	check.cond(&cond, e.Cond)
+	check.exprWithHint(x, e.A, hint)
+	check.exprWithHint(&y, e.B, hint)
+	if cond.mode == invalid || x.mode == invalid || y.mode == invalid {
+		x.mode = invalid
+		return
+	}
+
+	// a constant condition selects one of the operands
+	if cond.mode == constant && isBoolean(cond.typ) && x.mode == constant && y.mode == constant {
+		if !boolVal(cond.val) {
+			*x = y
+		}
+		return
+	}
+
+	check.matchTypes(x, &y)
+	for _, z := range []*operand{x, &y} {
+		if hint != nil {
+			check.implicitType(z, hint)
+		}
+		check.defaultType(z)
+	}
+	if x.mode == invalid || y.mode == invalid {
+		x.mode = invalid
+		return
+	}
+
+	if !Identical(x.typ, y.typ) {
+		check.errorf(e.Pos(), "mismatched types %s and %s in ternary expression", x.typ, y.typ)
+		x.mode = invalid
+		return
+	}
+	x.mode = value
+}
+
+func (check *checker) cast(x *operand, e *syntax.CastExpr) {
+	// a cast of a compound literal provides its type
+	if c, ok := e.X.(*syntax.CompoundExpr); ok {
+		check.compound(x, c, check.litType(e.Type))
+		return
+	}
+
+	check.castTo(x, check.typ(e.Type), e.X)
+}
+
+// castTo type-checks the operand e of a cast to the type T, and initializes x
+// with the result.
+func (check *checker) castTo(x *operand, T *Type, e syntax.Expr) {
+	switch e := e.(type) {
+	case *syntax.NoneExpr:
+		check.none(x, e, T)
+		return
+	case *syntax.CompoundExpr:
+		check.compound(x, e, T)
+		return
+	}
+
+	check.expr(x, e)
+	if x.mode == invalid || !isValid(T) {
+		x.mode = invalid
+		return
+	}
+	check.conversion(x, T)
+}
+
+func (check *checker) index(x *operand, e *syntax.IndexExpr) {
+	var i operand
+	check.expr(x, e.X)
+	check.expr(&i, e.Index)
+	if x.mode == invalid || i.mode == invalid {
+		x.mode = invalid
+		return
+	}
+
+	if !isIntegral(i.typ) {
+		check.errorf(e.Index.Pos(), "invalid index %s (must be integral)", &i)
+		x.mode = invalid
+		return
+	}
+
+	switch x.typ.kind {
+	case TARRAY:
+		length := x.typ.extra.(*Array).Length
+		if i.mode == constant {
+			if n, ok := int64Val(i.val); !ok || n < 0 || n >= int64(length) {
+				check.errorf(e.Index.Pos(), "index %s out of range [0:%d]", i.val, length)
+				x.mode = invalid
+				return
+			}
+		}
+		if x.mode == constant && i.mode == constant {
+			n, _ := int64Val(i.val)
+			x.val = ElemValue(x.val, int(n))
+		} else if x.mode != variable {
+			x.mode = value
+		}
+		x.typ = x.typ.Elem()
+
+	case TPOINTER:
+		// pointer indexing is pointer addition
+		x.mode = value
+
+	default:
+		check.errorf(e.Pos(), "invalid operation: cannot index %s", x)
+		x.mode = invalid
+	}
+}
+
+// selector type-checks the selection of a struct field. The field is
+// addressable if the struct itself is addressable, and if the field is not
+// declared const.
+func (check *checker) selector(x *operand, e *syntax.SelectorExpr) {
+	check.expr(x, e.X)
+	if x.mode == invalid {
+		return
+	}
+
+	if x.typ.kind != TSTRUCT {
+		check.errorf(e.Sel.Pos(), "invalid operation: cannot select field %s of %s", e.Sel.Value, x)
+		x.mode = invalid
+		return
+	}
+
+	f, i := x.typ.Field(e.Sel.Value)
+	if f == nil {
+		check.errorf(e.Sel.Pos(), "no field %s in struct of type %s", e.Sel.Value, x.typ)
+		x.mode = invalid
+		return
+	}
+
+	if x.mode == constant {
+		x.val = ElemValue(x.val, i)
+	} else if x.mode != variable || f.Const {
+		x.mode = value
+	}
+	x.typ = f.Type
+}
+
+// none type-checks the literal none as the absent value of the option type
+// T. If T is not an option type, the literal lacks a type and an error is
+// reported.
+func (check *checker) none(x *operand, e *syntax.NoneExpr, T *Type) {
+	if T == nil || T.kind != TOPTION {
+		if T == nil || isValid(T) {
+			check.errorf(e.Pos(), "none used without an option type")
+		}
+		return
+	}
+	x.mode = constant
+	x.typ = T
+	x.val = MakeNone(T)
+}
+
+func isNone(e syntax.Expr) bool {
+	_, ok := e.(*syntax.NoneExpr)
+	return ok
+}
+
+// noneType returns the type of none as the other operand of a binary
+// operation with x.
+func noneType(x *operand) *Type {
+	if x.mode == invalid {
+		return Types[TUNDEF] // error reported elsewhere
+	}
+	return x.typ
+}
+
+// compound type-checks a compound literal of type T. If T is nil, the
+// literal lacks a type and an error is reported.
+func (check *checker) compound(x *operand, e *syntax.CompoundExpr, T *Type) {
+	if T == nil {
+		check.errorf(e.Pos(), "missing type for compound literal")
+		check.useExprs(e.List)
+		return
+	}
+	if !isValid(T) {
+		check.useExprs(e.List)
+		return
+	}
+
+	var val Value
+	switch T.kind {
+	case TARRAY:
+		val = check.arrayLit(e, T)
+		if Sizeable(T) && !fitsSize(T) {
+			// the length of [_]T is only known now
+			check.errorf(e.Pos(), "type %s too large", T)
+			return
+		}
+	case TSTRUCT:
+		val = check.structLit(e, T)
+	default:
+		check.errorf(e.Pos(), "invalid compound literal type %s", T)
+		check.useExprs(e.List)
+		return
+	}
+
+	x.mode = value
+	x.typ = T
+	if val != nil {
+		x.mode = constant
+		x.val = val
+	}
+}
+
+// arrayLit type-checks the elements of an array literal of type T. If all
+// elements are constant, it returns the value of the literal, with omitted
+// elements being zero. Otherwise, it returns nil.
+//
+// If the length of T is not known yet, as for [_]T, it is set to one more
+// than the largest index of the elements.
+func (check *checker) arrayLit(e *syntax.CompoundExpr, T *Type) Value {
+	a := T.extra.(*Array)
+	infer := a.Length < 0
+	seen := make(map[int64]bool)
+
+	// elems is nil as soon as an element is not constant
+	var elems map[int]Value
+	if ZeroValue(a.Elem) != Undefined {
+		elems = make(map[int]Value)
+	}
+
+	var index, length int64
+	for _, elem := range e.List {
+		if kv, ok := elem.(*syntax.AssignExpr); ok {
+			ix, ok := kv.Lhs.(*syntax.IndexExpr)
+			if !ok {
+				check.errorf(kv.Pos(), "invalid field assignment in array literal of type %s", T)
+				check.useExprs([]syntax.Expr{kv.Rhs})
+				continue
+			}
+			var i operand
+			check.expr(&i, ix.Index)
+			if i.mode == invalid {
+				check.useExprs([]syntax.Expr{kv.Rhs})
+				continue
+			}
+			n, ok := int64Val(i.val)
+			if i.mode != constant || !isIntegral(i.typ) || !ok {
+				check.errorf(ix.Index.Pos(), "index %s must be a constant integer", &i)
+				check.useExprs([]syntax.Expr{kv.Rhs})
+				continue
+			}
+			index = n
+			elem = kv.Rhs
+		}
+
+		var inRange bool
+		switch {
+		case infer:
+			inRange = index >= 0 && index < math.MaxInt32
+			if !inRange {
+				check.errorf(elem.Pos(), "invalid index %d in array literal", index)
+			}
+		default:
+			inRange = index >= 0 && index < int64(a.Length)
+			if !inRange {
+				check.errorf(elem.Pos(), "index %d out of range [0:%d]", index, a.Length)
+			}
+		}
+		if inRange && seen[index] {
+			check.errorf(elem.Pos(), "duplicate index %d in array literal", index)
+		}
+		seen[index] = true
+
+		var x operand
+		check.exprWithHint(&x, elem, a.Elem)
+		check.assignment(&x, a.Elem, "array literal")
+		if x.mode != constant {
+			elems = nil
+		} else if elems != nil && inRange {
+			elems[int(index)] = x.val
+		}
+		if inRange {
+			length = max(length, index+1)
+		}
+		index++
+	}
+
+	if infer {
+		a.Length = int32(length)
+	}
+	if elems == nil {
+		return nil
+	}
+	return makeArray(T, elems)
+}
+
+// structLit type-checks the elements of a struct literal of type T. If all
+// elements are constant, it returns the value of the literal, with omitted
+// fields being zero. Otherwise, it returns nil.
+func (check *checker) structLit(e *syntax.CompoundExpr, T *Type) Value {
+	fields := T.extra.(*Struct).Fields
+
+	var vals []Value
+	if zero := ZeroValue(T); zero != Undefined {
+		vals = slices.Clone(zero.(structValue).fields)
+	}
+	set := func(i int, x *operand) {
+		if x.mode != constant {
+			vals = nil
+		} else if vals != nil {
+			vals[i] = x.val
+		}
+	}
+	result := func() Value {
+		if vals == nil {
+			return nil
+		}
+		return MakeStruct(T, vals)
+	}
+
+	if len(e.List) == 0 {
+		return result()
+	}
+
+	if _, ok := e.List[0].(*syntax.AssignExpr); ok {
+		// all elements must be field assignments
+		seen := make(map[string]bool)
+		for _, elem := range e.List {
+			kv, ok := elem.(*syntax.AssignExpr)
+			if !ok {
+				check.errorf(elem.Pos(), "mixture of field assignments and values in struct literal")
+				check.useExprs([]syntax.Expr{elem})
+				continue
+			}
+			name, ok := kv.Lhs.(*syntax.Name)
+			if !ok {
+				check.errorf(kv.Pos(), "invalid index assignment in struct literal of type %s", T)
+				check.useExprs([]syntax.Expr{kv.Rhs})
+				continue
+			}
+			f, i := T.Field(name.Value)
+			if f == nil {
+				check.errorf(name.Pos(), "unknown field %s in struct literal of type %s", name.Value, T)
+				check.useExprs([]syntax.Expr{kv.Rhs})
+				continue
+			}
+			if seen[f.Name] {
+				check.errorf(name.Pos(), "duplicate field %s in struct literal", f.Name)
+			}
+			seen[f.Name] = true
+
+			var x operand
+			check.exprWithHint(&x, kv.Rhs, f.Type)
+			check.assignment(&x, f.Type, "struct literal")
+			set(i, &x)
+		}
+		return result()
+	}
+
+	// all fields must be provided in order
+	for i, elem := range e.List {
+		if _, ok := elem.(*syntax.AssignExpr); ok {
+			check.errorf(elem.Pos(), "mixture of field assignments and values in struct literal")
+			continue
+		}
+		if i >= len(fields) {
+			check.errorf(elem.Pos(), "too many values in struct literal of type %s", T)
+			check.useExprs(e.List[i:])
+			return nil
+		}
+		var x operand
+		check.exprWithHint(&x, elem, fields[i].Type)
+		check.assignment(&x, fields[i].Type, "struct literal")
+		set(i, &x)
+	}
+	if len(e.List) < len(fields) {
+		check.errorf(e.Pos(), "too few values in struct literal of type %s", T)
+		return nil
+	}
+	return result()
+}
+
+// procExpr type-checks a procedure literal. The procedure's body is checked
+// later if the literal is declared globally, otherwise it is checked right
+// away.
+func (check *checker) procExpr(x *operand, e *syntax.ProcExpr) {
+	typ := check.procType(e.Type)
+	proc := NewProc(typ, nil, check.scope, e)
+	check.mod.procs[e] = proc
+	proc.params = check.buildParams(e.Type, typ.extra.(*Signature), proc.body)
+
+	if check.proc == nil {
+		check.later(func() { check.procBody(proc) })
+	} else {
+		check.procBody(proc)
+	}
+
+	x.mode = value
+	x.typ = typ
+}
+
+// buildParams creates a symbol for each parameter of the procedure literal
+// with type e and signature sig, and declares it in the procedure body scope.
+// Contrary to procedure types, all parameters of a procedure literal must be
+// named, though they may be named _.
+func (check *checker) buildParams(e *syntax.ProcType, sig *Signature, scope *Scope) []*Symbol {
+	var params []*Symbol
+	for i, f := range e.ParamList {
+		if f.Name == nil {
+			check.errorf(f.Pos(), "missing parameter name")
+			continue
+		}
+		// parameters are part of the signature, so they need not be used
+		sym := &Symbol{name: f.Name.Value, pos: f.Name.Pos(), typ: sig.Params[i].Type, mod: check.mod, flags: symUsed}
+		if f.Const {
+			sym.flags |= symConst
+		}
+		params = append(params, sym)
+		if sym.name == "_" {
+			continue
+		}
+		if alt := scope.Insert(sym); alt != nil {
+			check.errorf(sym.pos, "duplicate parameter %s", sym.name)
+		}
+	}
+	return params
+}
+
+// useExprs type-checks the expressions in list for the sake of reporting
+// errors, and discards their values.
+func (check *checker) useExprs(list []syntax.Expr) {
+	for _, e := range list {
+		var x operand
+		check.rawExpr(&x, e, nil)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+// wantConsts compares the values of the constants declared in mod with want,
+// which holds pairs of a name and the expected value.
+func wantConsts(t *testing.T, mod *Module, want ...string) {
+	t.Helper()
+	for i := 0; i < len(want); i += 2 {
+		name := want[i]
+		sym := mod.Lookup(name)
+		if sym == nil || sym.flags&symStatic == 0 {
+			t.Errorf("%s is not a constant", name)
+			continue
+		}
+		if got := sym.extra.(Value).String(); got != want[i+1] {
+			t.Errorf("%s = %s, want %s", name, got, want[i+1])
+		}
+	}
+}
+
+func TestBinaryFolding(t *testing.T) {
+	mod := wantErrors(t, "const a = 1 + 2 * 3; const b = 7 / 2; const c = 1 << 4; const d = 1 < 2; const e = 1 == 2 ? 3 : 4;")
+	wantConsts(t, mod, "a", "7", "b", "3", "c", "16", "d", "true", "e", "4")
+}
+
+func TestIndexExpr(t *testing.T) {
+	wantErrors(t, "var a: [3]int32; var b = a[1]; var i: int32; var c = a[i];")
+	wantErrors(t, "var a: [3]int32; var b = a[3];", "1:28: index 3 out of range [0:3]")
+}
+
+func TestUnaryFolding(t *testing.T) {
+	mod := wantErrors(t, "const a = -5; const b = ~3; const c = !true; const d: uint8 = 3; const e = ~d; const f = -(-128);")
+	wantConsts(t, mod, "a", "-5", "b", "-4", "c", "false", "e", "252", "f", "128")
+
+	wantErrors(t, "const a: uint8 = ~3;", "1:18: constant -4 overflows uint8")
+	wantErrors(t, "const a: uint8 = -1;", "1:18: constant -1 overflows uint8")
+	wantErrors(t, "const a = -true;", "1:11: invalid operation: operator - not defined on true (untyped bool constant true)")
+}
+
+func TestDivisionByZero(t *testing.T) {
+	// errors are reported at the operator
+	wantErrors(t, "const a = 5 / 0;", "1:13: invalid operation: division by zero")
+	wantErrors(t, "const a = 5 % (1 - 1);", "1:13: invalid operation: division by zero")
+	wantErrors(t, "const a = 1 +\n\t5 / 0;", "2:4: invalid operation: division by zero")
+	wantErrors(t, "var v: int32; const f = proc() { v = v / 0; };", "1:40: invalid operation: division by zero")
+	wantErrors(t, "var v: float32; const f = proc() { v = v / 0; };")
+
+	wantErrors(t, "const a = true / 1;", "1:16: invalid operation: operator / not defined on true (untyped bool constant true)")
+}
+
+func TestSizeofExpr(t *testing.T) {
+	mod := wantErrors(t, "var v: int32; const a = sizeof(int32); const b = sizeof(v); const c = sizeof(v + 1); const d = sizeof(1.5);")
+	wantConsts(t, mod, "a", "4", "b", "4", "c", "4", "d", "8")
+
+	mod = wantErrors(t, "const a = sizeof(?*int32) == sizeof(*int32); const b = sizeof(?int32);")
+	wantConsts(t, mod, "a", "true", "b", "8")
+}
+
+func TestTypeTooLarge(t *testing.T) {
+	// 4294967295 bytes is the largest size a type may have
+	wantErrors(t, "var a: [2147483647][2]int8; var b: ?[2147483647][2]int8;")
+	wantErrors(t, "var s: struct{a: [2147483647]int8; b: [2147483647]int8; c: int8;};")
+
+	wantErrors(t, "const n = sizeof([2147483647]int64);", "1:18: type [2147483647]int64 too large")
+	wantErrors(t, "type T [2147483647][3]int8; var x: T;", "1:8: type [2147483647][3]int8 too large")
+	wantErrors(t, "type S struct{a: [2147483647]int8; b: [2147483647]int8; c: int8;}; var b: ?S;", "1:75: type ?S too large")
+	wantErrors(t, "var s: struct{a: [2147483647]int8; b: [2147483647]int8; c: int16;};",
+		"1:8: type struct{a: [2147483647]int8; b: [2147483647]int8; c: int16} too large")
+	wantErrors(t, "var v: int64; const n = sizeof(([_]int64){[536870912] = v});", "1:42: type [536870913]int64 too large")
+}
+
+func TestConstIndex(t *testing.T) {
+	mod := wantErrors(t, "const a = ([3]int32){1, 2, 3}; const b = a[2]; const c = a[0] + a[1];")
+	wantConsts(t, mod, "b", "3", "c", "3")
+
+	wantErrors(t, "const a = ([3]int32){1, 2, 3}; const b = a[3];", "1:44: index 3 out of range [0:3]")
+	wantErrors(t, "const a = ([3]int32){1, 2, 3}; const b = a[-1];", "1:44: index -1 out of range [0:3]")
+}
+
+func TestLargeConstArrays(t *testing.T) {
+	// constant arrays are stored sparsely, such that large ones are cheap
+	mod := wantErrors(t, "const a = ([2147483647]int8){}; const b = ([2147483647]int8){[2147483646] = 1}; "+
+		"const n = sizeof(a); const x = a[2147483646]; const y = b[2147483646]; const eq = a == b; "+
+		"const c = ([2147483647]int8){[2147483646] = 1}; const eq2 = b == c;")
+	wantConsts(t, mod,
+		"a", "[2147483647]int8{}",
+		"b", "[2147483647]int8{[2147483646] = 1}",
+		"n", "2147483647",
+		"x", "0",
+		"y", "1",
+		"eq", "false",
+		"eq2", "true")
+
+	mod = wantErrors(t, "const g = ([_][1000000]int8){[1000] = {[5] = 1}}; const h = g[1000][5] + g[999][5];")
+	wantConsts(t, mod, "h", "1")
+}
+
+func TestConstantOverflow(t *testing.T) {
+	wantErrors(t, "const a: int64 = 9223372036854775807 + 1;", "1:38: constant 9223372036854775808 overflows int64")
+	wantErrors(t, "const a: int32 = 2147483647; const b = a * a;", "1:42: constant 4611686014132420609 overflows int32")
+	wantErrors(t, "const a: int64 = 2147483647 * 2147483647;")
+
+	mod := wantErrors(t, "const q = -9223372036854775808 / -1; const r = -5 / 18446744073709551615; const s = -5 % 18446744073709551615;")
+	wantConsts(t, mod, "q", "9223372036854775808", "r", "0", "s", "-5")
+	wantErrors(t, "const m: int64 = -9223372036854775808; const q = m / -1;", "1:52: constant 9223372036854775808 overflows int64")
+}
+
+func TestBigConstants(t *testing.T) {
+	mod := wantErrors(t, "const a = 1 << 100; const b: int64 = a >> 90; const c = a >> 98; const d = (int64)c;")
+	wantConsts(t, mod, "a", "1267650600228229401496703205376", "b", "1024", "d", "4")
+
+	wantErrors(t, "const a = 1 << 100; const b: int64 = a;", "1:38: constant 1267650600228229401496703205376 overflows int64")
+	wantErrors(t, "const a = 1 << 100; const b = (int64)a;", "1:38: cannot convert a (untyped int constant 1267650600228229401496703205376) to type int64 (overflows)")
+}
+
+func TestUntypedConstants(t *testing.T) {
+	mod := wantErrors(t, "const a = 1 + 2.0; const b = 1; var c: uint8 = b; var d = b;")
+	for _, test := range []struct{ name, typ string }{{"a", "untyped float"}, {"b", "untyped int"}, {"c", "uint8"}, {"d", "int32"}} {
+		if got := mod.Lookup(test.name).typ.String(); got != test.typ {
+			t.Errorf("%s is of type %s, want %s", test.name, got, test.typ)
+		}
+	}
+
+	src := "type S struct{b: uint8;}; var s: S; const f = proc() { s.b = 255; s.b = 256; };"
+	wantErrors(t, src, "1:73: constant 256 overflows uint8")
+}
+
+func TestFieldSelection(t *testing.T) {
+	mod := wantErrors(t, "type P struct{x: int32; y: bool;}; var p: P; var a = p.x; var b = p.y; const f = proc() { p.x = 1; };")
+	for _, test := range []struct{ name, typ string }{{"a", "int32"}, {"b", "bool"}} {
+		if got := mod.Lookup(test.name).typ.String(); got != test.typ {
+			t.Errorf("%s is of type %s, want %s", test.name, got, test.typ)
+		}
+	}
+
+	wantErrors(t, "type P struct{x: int32;}; var p: P; var a = p.z;", "1:47: no field z in struct of type P")
+	wantErrors(t, "type P struct{x: int32;}; const g = proc() P { var p: P; return p; }; const f = proc() { g().x = 1; };", "1:93: cannot assign to value of type int32")
+}
+
+func TestOperatorErrors(t *testing.T) {
+	// operators are named by their source spelling
+	wantErrors(t, "var x: int32; var b: bool; var y = x << b;", "1:38: invalid operation: int32 << bool (shift count must be integral)")
+	wantErrors(t, "var x: bool; var y = x + x;", "1:24: invalid operation: operator + not defined on x (variable of type bool)")
+	wantErrors(t, "var x: bool; var y = ~x;", "1:22: invalid operation: operator ~ not defined on x (variable of type bool)")
+}
+
+func TestBoolCast(t *testing.T) {
+	mod := wantErrors(t, "const a = (int32)true; const b = (uint8)false; const t = 1 < 2; const c = (int32)t;")
+	wantConsts(t, mod, "a", "1", "b", "0", "c", "1")
+
+	wantErrors(t, "const a = (float32)true;", "1:20: cannot convert true (untyped bool constant true) to type float32")
+	wantErrors(t, "const a = (bool)1;", "1:17: cannot convert untyped int constant 1 to type bool")
+}
+
+func TestPointerComparison(t *testing.T) {
+	wantErrors(t, "var p, q: *int32; var a = p == q; var b = p != q;")
+	wantErrors(t, "var p: *int32; var q: *const int32; var a = p == q;")
+	wantErrors(t, "var p: *int32; var r: *float32; var a = p == r;", "1:43: invalid operation: mismatched pointer types in comparison (*int32 and *float32)")
+	wantErrors(t, "var p: *int32; var a = p == &p;", "1:26: invalid operation: mismatched pointer types in comparison (*int32 and **int32)")
+
+	// pointers are never nil, their absence is expressed by an option
+	wantErrors(t, "var o: ?*int32; var a = o == none; var b = none != o;")
+	wantErrors(t, "var p: *int32; var a = p == none;", "1:29: none used without an option type")
+}
+
+func TestStructComparison(t *testing.T) {
+	mod := wantErrors(t, "type P struct{x: int32; y: bool;}; const a = (P){.x = 1, .y = true}; const b = (P){.x = 1, .y = true}; "+
+		"const c = (P){.x = 2}; const d = a == b; const e = a != c; const f = a == c;")
+	wantConsts(t, mod, "d", "true", "e", "true", "f", "false")
+
+	// structs compare if all their fields do
+	wantErrors(t, "type R struct{a: [2]int32; p: *int32;}; var r: R; var b = r == r;")
+	wantErrors(t, "type Q struct{f: proc();}; var q: Q; var b = q == q;", "1:48: invalid operation: operator == not defined on q (variable of type Q)")
+}
+
+func TestAsCast(t *testing.T) {
+	mod := wantErrors(t, "var x: int32; var y = x as int64; const a = 300 - 100 as int8; const b = 1 < 2 as uint8;")
+	wantConsts(t, mod, "a", "-56", "b", "1")
+	if got := mod.Lookup("y").typ.String(); got != "int64" {
+		t.Errorf("y is of type %s, want int64", got)
+	}
+	wantErrors(t, "var x: bool; var y = x as float32;", "1:22: cannot convert x (variable of type bool) to type float32")
+}
+
+func TestInferredArrayLength(t *testing.T) {
+	mod := wantErrors(t, "const a = ([_]int32){1, 2, 3}; var b = ([_]int8){[4] = 1, 2}; const c = ([_]int32){};")
+	for _, test := range []struct {
+		name string
+		len  int32
+	}{{"a", 3}, {"b", 6}, {"c", 0}} {
+		if got := mod.Lookup(test.name).typ.extra.(*Array).Length; got != test.len {
+			t.Errorf("%s has length %d, want %d", test.name, got, test.len)
+		}
+	}
+
+	wantErrors(t, "var a: [_]int32;", "1:9: invalid use of [_] array outside of a compound literal")
+	wantErrors(t, "type T [_]int32;", "1:9: invalid use of [_] array outside of a compound literal")
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the structural hashing of types.
+
+package types
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+)
+
+// hashType returns a hash of the structure of t, such that identical types
+// have the same hash. Named types are hashed by name only, which also ends
+// the recursion of recursive types. As with Identical, parameter names of
+// procedure types are ignored. The hash is stable across runs.
+func hashType(t *Type) uint64 {
+	h := typeHasher{h: fnv.New64a()}
+	h.typ(t)
+	return h.h.Sum64()
+}
+
+type typeHasher struct {
+	h   hash.Hash64
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (h *typeHasher) uint(x uint64) {
+	h.h.Write(binary.AppendUvarint(h.buf[:0], x))
+}
+
+func (h *typeHasher) string(s string) {
+	h.uint(uint64(len(s)))
+	h.h.Write([]byte(s))
+}
+
+func (h *typeHasher) bool(b bool) {
+	if b {
+		h.uint(1)
+	} else {
+		h.uint(0)
+	}
+}
+
+func (h *typeHasher) typ(t *Type) {
+	h.uint(uint64(t.kind))
+	if t.sym != nil {
+		h.string(t.sym.name)
+		return
+	}
+
+	switch t.kind {
+	case TPOINTER:
+		p := t.extra.(*Pointer)
+		h.bool(p.Const)
+		h.typ(p.Elem)
+
+	case TOPTION:
+		h.typ(t.Elem())
+
+	case TARRAY:
+		a := t.extra.(*Array)
+		h.uint(uint64(a.Length))
+		h.typ(a.Elem)
+
+	case TPROC:
+		s := t.extra.(*Signature)
+		h.uint(uint64(len(s.Params)))
+		for _, f := range s.Params {
+			h.bool(f.Const)
+			h.typ(f.Type)
+		}
+		h.typ(s.Result)
+
+	case TSTRUCT:
+		s := t.extra.(*Struct)
+		h.uint(uint64(len(s.Fields)))
+		for _, f := range s.Fields {
+			h.string(f.Name)
+			h.bool(f.Const)
+			h.typ(f.Type)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package types
 import (
 	"cobalt/base"
 	"cobalt/src"
+	"cobalt/syntax"
 )
 
 var modmap map[string]*Module
@@ -15,9 +16,23 @@ var modmap map[string]*Module
 type Module struct {
 	name, path string
 	scope      *Scope
+
+	// names maps the names in the checked source files of the module to the
+	// symbols they refer to.
+	names map[*syntax.Name]*Symbol
+
+	// procs maps the procedure literals in the checked source files of the
+	// module to their procedures.
+	procs map[*syntax.ProcExpr]*Proc
 }
 
+// NewModule returns the module at path, creating it if necessary. The scope
+// of a new module is nested in the Universe scope, so that built-in symbols
+// are visible from within the module. [Init] must have been called before.
 func NewModule(name, path string) *Module {
+	if Universe == nil {
+		base.Fatalf("types: NewModule called before Init")
+	}
 	if mod := modmap[path]; mod != nil {
 		if name != "" && name != mod.name {
 			base.Fatalf("conflicting module names %s and %s for path %q", name, mod.name, path)
@@ -29,6 +44,8 @@ func NewModule(name, path string) *Module {
 	mod.path = path
 	mod.name = name
 	mod.scope = NewScope(Universe, src.NoPos, src.NoPos)
+	mod.names = make(map[*syntax.Name]*Symbol)
+	mod.procs = make(map[*syntax.ProcExpr]*Proc)
 	modmap[path] = mod
 
 	return mod
@@ -38,3 +55,10 @@ func (mod *Module) Name() string                     { return mod.name }
 func (mod *Module) Path() string                     { return mod.path }
 func (mod *Module) Lookup(name string) *Symbol       { return mod.scope.Lookup(name) }
 func (mod *Module) Insert(sym *Symbol) (alt *Symbol) { return mod.scope.Insert(sym) }
+
+// ResolvedSymbol returns the symbol the name n in a source file of mod refers
+// to, or nil if n has not been resolved, such as if it is undefined or has not
+// been checked yet.
+func (mod *Module) ResolvedSymbol(n *syntax.Name) *Symbol {
+	return mod.names[n]
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"cobalt/syntax"
+	"strings"
+)
+
+// An operandMode specifies the (addressing) mode of an operand.
+type operandMode uint8
+
+const (
+	invalid  operandMode = iota // operand is invalid
+	novalue                     // operand represents no value (result of a void procedure call)
+	builtin                     // operand is a built-in procedure
+	typexpr                     // operand is a type
+	constant                    // operand is a constant; the operand's val field is valid
+	variable                    // operand is an addressable variable
+	value                       // operand is a computed value
+)
+
+var operandModeString = [...]string{
+	invalid:  "invalid operand",
+	novalue:  "no value",
+	builtin:  "built-in",
+	typexpr:  "type",
+	constant: "constant",
+	variable: "variable",
+	value:    "value",
+}
+
+// An operand represents an intermediate value during type checking. Operands
+// have an (addressing) mode, the expression evaluating to the operand, the
+// operand's type and a value for constants.
+type operand struct {
+	mode operandMode
+	expr syntax.Expr
+	typ  *Type
+	val  Value   // valid if mode == constant
+	id   Builtin // valid if mode == builtin
+}
+
+// String returns a human-readable description of x, for use in error
+// messages. Examples:
+//
+//	x (variable of type int32)
+//	constant 5 of type int32
+//	untyped int constant 5
+//	value of type *int32
+func (x *operand) String() string {
+	var b strings.Builder
+
+	name, _ := x.expr.(*syntax.Name)
+	if name != nil {
+		b.WriteString(name.Value)
+		b.WriteString(" (")
+	}
+
+	if x.mode == constant && x.typ != nil && isUntyped(x.typ) {
+		b.WriteString(x.typ.String())
+		b.WriteString(" constant ")
+		b.WriteString(x.val.String())
+		if name != nil {
+			b.WriteByte(')')
+		}
+		return b.String()
+	}
+
+	b.WriteString(operandModeString[x.mode])
+	if x.mode == constant {
+		b.WriteByte(' ')
+		b.WriteString(x.val.String())
+	}
+	if x.mode != invalid && x.mode != novalue && x.mode != builtin && x.typ != nil {
+		if x.mode == typexpr {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(" of type ")
+		}
+		b.WriteString(x.typ.String())
+	}
+
+	if name != nil {
+		b.WriteByte(')')
+	}
+	return b.String()
+}
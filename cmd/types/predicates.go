@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements commonly used type predicates.
+
+package types
+
+// Identical reports whether t and u are identical types. Named types are only
+// identical to themselves, other types are identical if they are structurally
+// equivalent. Parameter names are ignored for procedure types.
+func Identical(t, u *Type) bool {
+	if t == u {
+		return true
+	}
+	if t == nil || u == nil || t.sym != nil || u.sym != nil || t.kind != u.kind {
+		return false
+	}
+
+	switch t.kind {
+	case TPOINTER:
+		p, q := t.extra.(*Pointer), u.extra.(*Pointer)
+		return p.Const == q.Const && Identical(p.Elem, q.Elem)
+
+	case TOPTION:
+		return Identical(t.Elem(), u.Elem())
+
+	case TARRAY:
+		a, b := t.extra.(*Array), u.extra.(*Array)
+		return a.Length == b.Length && Identical(a.Elem, b.Elem)
+
+	case TPROC:
+		s, r := t.extra.(*Signature), u.extra.(*Signature)
+		if len(s.Params) != len(r.Params) || !Identical(s.Result, r.Result) {
+			return false
+		}
+		for i, f := range s.Params {
+			if f.Const != r.Params[i].Const || !Identical(f.Type, r.Params[i].Type) {
+				return false
+			}
+		}
+		return true
+
+	case TSTRUCT:
+		s, r := t.extra.(*Struct), u.extra.(*Struct)
+		if len(s.Fields) != len(r.Fields) {
+			return false
+		}
+		for i, f := range s.Fields {
+			g := r.Fields[i]
+			if f.Name != g.Name || f.Const != g.Const || !Identical(f.Type, g.Type) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// AssignableTo reports whether a value of type V may be assigned to a variable
+// of type T. Values are assignable to identical types, to optional types of
+// their own type, and pointers are assignable to pointers-to-const of the same
+// element type. Untyped booleans are assignable to boolean types and untyped
+// numbers to numeric types, or to options of these. Whether a particular
+// untyped constant fits T depends on its value, which is not considered.
+func AssignableTo(V, T *Type) bool {
+	if isUntyped(V) {
+		if T.kind == TOPTION {
+			T = T.Elem()
+		}
+		return isBoolean(V) && isBoolean(T) || isNumeric(V) && isNumeric(T)
+	}
+
+	switch {
+	case Identical(V, T):
+		return true
+	case T.kind == TOPTION:
+		return Identical(V, T.Elem())
+	case V.kind == TPOINTER && T.kind == TPOINTER:
+		return T.extra.(*Pointer).Const && Identical(V.Elem(), T.Elem())
+	}
+	return false
+}
+
+// ConvertibleTo reports whether a value of type V may be converted to type T
+// by a cast. Besides values assignable to T, numeric values convert to any
+// numeric type, booleans to integers, where true is 1 and false is 0, and
+// pointers to other pointers as well as to and from uintptr, the only integer
+// type large enough to hold an address. A pointer to const only converts to
+// other pointers to const. Like AssignableTo, ConvertibleTo does not consider
+// the values of untyped constants.
+func ConvertibleTo(V, T *Type) bool {
+	switch {
+	case AssignableTo(V, T):
+		return true
+	case isNumeric(V) && isNumeric(T):
+		return true
+	case isPointer(V) && isPointer(T):
+		return !V.extra.(*Pointer).Const || T.extra.(*Pointer).Const
+	case isPointer(V) && T.kind == TUINTPTR, V.kind == TUINTPTR && isPointer(T):
+		return true
+	case isBoolean(V) && isBoolean(T):
+		return true
+	case isBoolean(V) && isIntegral(T):
+		return true
+	}
+	return false
+}
+
+// isVoid reports whether t is the absent result type of a procedure.
+func isVoid(t *Type) bool {
+	return t == nil || t.kind == TVOID
+}
+
+// isValid reports whether t is a valid type, i.e. not the result of an error.
+func isValid(t *Type) bool {
+	return t != nil && t.kind != TUNDEF
+}
+
+func isBoolean(t *Type) bool  { return t.kind == TBOOL || t.kind == TUNTYPEDBOOL }
+func isNumeric(t *Type) bool  { return t.kind.IsNumeric() }
+func isIntegral(t *Type) bool { return t.kind.IsIntegral() }
+func isPointer(t *Type) bool  { return t.kind == TPOINTER }
+func isUntyped(t *Type) bool  { return t.kind.IsUntyped() }
+
+// comparable reports whether values of type t can be compared with the
+// equality operators.
+func comparable(t *Type) bool {
+	switch t.kind {
+	case TARRAY, TOPTION:
+		return comparable(t.Elem())
+	case TSTRUCT:
+		for _, f := range t.extra.(*Struct).Fields {
+			if !comparable(f.Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return t.kind.IsComparable()
+}
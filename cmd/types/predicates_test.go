@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestIdentical(t *testing.T) {
+	i32, i64 := Types[TINT32], Types[TINT64]
+	identical := [][2]*Type{
+		{i32, i32},
+		{NewPointer(i32, true), NewPointer(i32, true)},
+		{NewOption(i32), NewOption(i32)},
+		{NewArray(i32, 4), NewArray(i32, 4)},
+		// parameter names are ignored
+		{NewSignature([]*Field{{Name: "a", Type: i32}}, i64), NewSignature([]*Field{{Name: "b", Type: i32}}, i64)},
+		{NewStruct([]*Field{{Name: "x", Type: i32}}), NewStruct([]*Field{{Name: "x", Type: i32}})},
+	}
+	for _, p := range identical {
+		if !Identical(p[0], p[1]) {
+			t.Errorf("%s and %s are not identical", p[0], p[1])
+		}
+	}
+
+	different := [][2]*Type{
+		{i32, i64},
+		{NewPointer(i32, false), NewPointer(i32, true)},
+		{NewOption(i32), NewPointer(i32, false)},
+		{NewArray(i32, 4), NewArray(i32, 5)},
+		{NewSignature([]*Field{{Type: i32}}, nil), NewSignature([]*Field{{Type: i32, Const: true}}, nil)},
+		// field names are not ignored
+		{NewStruct([]*Field{{Name: "x", Type: i32}}), NewStruct([]*Field{{Name: "y", Type: i32}})},
+	}
+	for _, p := range different {
+		if Identical(p[0], p[1]) {
+			t.Errorf("%s and %s are identical", p[0], p[1])
+		}
+	}
+}
+
+func TestAssignableConvertible(t *testing.T) {
+	i32, i64, b := Types[TINT32], Types[TINT64], Types[TBOOL]
+	p, cp := NewPointer(i32, false), NewPointer(i32, true)
+	for _, test := range []struct {
+		V, T                    *Type
+		assignable, convertible bool
+	}{
+		{i32, i32, true, true},
+		{i32, i64, false, true},
+		{Types[TFLOAT64], i32, false, true},
+		{i32, NewOption(i32), true, true},
+		{b, b, true, true},
+		{b, i32, false, true}, // true is 1, false is 0
+		{i32, b, false, false},
+		{p, cp, true, true},
+		{cp, p, false, false}, // casts do not drop const
+		{p, NewPointer(i64, false), false, true},
+
+		// only uintptr holds an address
+		{p, Types[TUINTPTR], false, true},
+		{Types[TUINTPTR], p, false, true},
+		{p, Types[TINTPTR], false, false},
+		{p, i64, false, false},
+		{i64, p, false, false},
+
+		// untyped constants, regardless of their value
+		{Types[TUNTYPEDINT], Types[TUINT8], true, true},
+		{Types[TUNTYPEDFLOAT], i32, true, true},
+		{Types[TUNTYPEDINT], NewOption(i64), true, true},
+		{Types[TUNTYPEDBOOL], b, true, true},
+		{Types[TUNTYPEDBOOL], i32, false, true},
+		{Types[TUNTYPEDINT], b, false, false},
+		{Types[TUNTYPEDINT], p, false, false},
+	} {
+		if got := AssignableTo(test.V, test.T); got != test.assignable {
+			t.Errorf("AssignableTo(%s, %s) = %v, want %v", test.V, test.T, got, test.assignable)
+		}
+		if got := ConvertibleTo(test.V, test.T); got != test.convertible {
+			t.Errorf("ConvertibleTo(%s, %s) = %v, want %v", test.V, test.T, got, test.convertible)
+		}
+	}
+}
+
+func TestPointerIntegerCasts(t *testing.T) {
+	wantErrors(t, "var p: *int32; var u = (uintptr)p; var q = (*int32)u;")
+	wantErrors(t, "var p: *int32; var i = (int64)p;", "1:31: cannot convert p (variable of type *int32) to type int64")
+	wantErrors(t, "var i: int64; var q = (*int32)i;", "1:31: cannot convert i (variable of type int64) to type *int32")
+}
@@ -9,8 +9,6 @@ import (
 	"cobalt/syntax"
 )
 
-var procmap map[*syntax.ProcExpr]*Proc
-
 // Proc represents a singular procedure, with its own type and body.
 type Proc struct {
 	pos src.Pos // position of "proc"
@@ -20,7 +18,8 @@ type Proc struct {
 	params []*Symbol // parameters, in order
 	code   *syntax.BlockStmt
 
-	flags uint32
+	callees []*Proc // procedures called by p, if known statically
+	flags   uint32
 }
 
 const (
@@ -30,17 +29,11 @@ const (
 )
 
 func NewProc(typ *Type, params []*Symbol, parent *Scope, node *syntax.ProcExpr) *Proc {
-	if proc, ok := procmap[node]; ok {
-		return proc
-	}
-
 	proc := new(Proc)
 	proc.pos = node.Pos()
 	proc.typ = typ
 	proc.body = NewScope(parent, node.Body.Pos(), node.Body.Closing)
 	proc.params = params
 	proc.code = node.Body
-
-	procmap[node] = proc
 	return proc
 }
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the purity analysis of procedures. A procedure is pure
+// if it does not write to state outside of itself, and only calls procedures
+// which are pure. Procedures may read any state.
+//
+// While a procedure body is checked, its writes and calls are recorded: a
+// write to a non-local variable makes the procedure impure right away, and
+// so does a call of a procedure which is not known statically. Calls of known
+// procedures are kept, and once all bodies are checked, impurity is
+// propagated from callees to callers.
+
+package types
+
+import "cobalt/syntax"
+
+// recordWrite records a write to the variable e in the current procedure.
+func (check *checker) recordWrite(e syntax.Expr) {
+	p := check.proc
+	if p == nil || p.flags&procPure == 0 {
+		return
+	}
+
+	for {
+		switch x := e.(type) {
+		case *syntax.Name:
+			if sym := check.mod.names[x]; sym == nil || !p.isLocal(sym) {
+				p.flags &^= procPure
+			}
+			return
+		case *syntax.SelectorExpr:
+			e = x.X
+		case *syntax.IndexExpr:
+			e = x.X
+		default:
+			// writes through pointers may change any state
+			p.flags &^= procPure
+			return
+		}
+	}
+}
+
+// recordCall records a call of the procedure e in the current procedure.
+func (check *checker) recordCall(e syntax.Expr) {
+	p := check.proc
+	if p == nil || p.flags&procPure == 0 {
+		return
+	}
+
+	if n, ok := e.(*syntax.Name); ok {
+		if sym := check.mod.names[n]; sym != nil && sym.flags&symConst != 0 {
+			if callee, ok := sym.extra.(*Proc); ok {
+				p.callees = append(p.callees, callee)
+				return
+			}
+		}
+	}
+	p.flags &^= procPure // unknown callee
+}
+
+// purity propagates impurity from the procedures checked to their callers.
+func (check *checker) purity() {
+	for changed := true; changed; {
+		changed = false
+		for _, p := range check.procs {
+			if p.flags&procPure == 0 {
+				continue
+			}
+			for _, callee := range p.callees {
+				if callee.flags&procPure == 0 {
+					p.flags &^= procPure
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// isLocal reports whether sym is declared within the body of p, including its
+// parameters. Symbols of enclosing procedures are not local.
+func (p *Proc) isLocal(sym *Symbol) bool {
+	for s := sym.scope; s != nil; s = s.parent {
+		if s == p.body {
+			return true
+		}
+	}
+	return false
+}
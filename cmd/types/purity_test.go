@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestPurity(t *testing.T) {
+	mod := wantErrors(t, "var g: int32; "+
+		"const add = proc(a: int32, b: int32) int32 { var c = a + b; c = c * 2; return c + g; }; "+
+		"const set = proc(x: int32) { g = x; }; "+
+		"const twice = proc(x: int32) int32 { return add(x, x); }; "+
+		"const setTwice = proc(x: int32) { set(x); set(x); }; "+
+		"const viaPtr = proc(p: *int32) { p.* = 1; }; "+
+		"const indirect = proc(f: proc()) { f(); }; "+
+		"const rec = proc(n: int32) int32 { return rec(n - 1); };")
+	for _, test := range []struct {
+		name string
+		pure bool
+	}{
+		{"add", true}, // reading globals is pure
+		{"set", false},
+		{"twice", true},
+		{"setTwice", false}, // calls an impure procedure
+		{"viaPtr", false},
+		{"indirect", false}, // the callee is unknown
+		{"rec", true},
+	} {
+		p := mod.Lookup(test.name).extra.(*Proc)
+		if got := p.flags&procPure != 0; got != test.pure {
+			t.Errorf("%s is pure: got %v, want %v", test.name, got, test.pure)
+		}
+	}
+}
@@ -4,17 +4,21 @@
 
 package types
 
-import "cobalt/src"
+import (
+	"cobalt/src"
+	"slices"
+)
 
 // Scope maintains a nested collection of symbols.
 type Scope struct {
 	parent   *Scope
 	elems    map[string]*Symbol
+	names    []string // names of elems in insertion order
 	pos, end src.Pos
 }
 
 func NewScope(parent *Scope, pos, end src.Pos) *Scope {
-	return &Scope{parent, nil, pos, end}
+	return &Scope{parent, nil, nil, pos, end}
 }
 
 func (s *Scope) Parent() *Scope { return s.parent }
@@ -22,6 +26,13 @@ func (s *Scope) Pos() src.Pos   { return s.pos }
 func (s *Scope) End() src.Pos   { return s.end }
 func (s *Scope) Len() int       { return len(s.elems) }
 
+// Names returns the names of the symbols in s, in the order they were
+// inserted. For the Universe, this is the order in which the builtins are
+// declared.
+func (s *Scope) Names() []string {
+	return slices.Clone(s.names)
+}
+
 func (s *Scope) Lookup(name string) *Symbol {
 	return s.elems[name]
 }
@@ -43,6 +54,7 @@ func (s *Scope) Insert(sym *Symbol) (alt *Symbol) {
 			s.elems = m
 		}
 		m[sym.name] = sym
+		s.names = append(s.names, sym.name)
 		if sym.scope == nil {
 			sym.scope = s
 		}
@@ -50,6 +62,28 @@ func (s *Scope) Insert(sym *Symbol) (alt *Symbol) {
 	return
 }
 
+// Delete removes the symbol with the provided name from s, if any. Symbols of
+// enclosing scopes are not affected.
+func (s *Scope) Delete(name string) {
+	if sym := s.elems[name]; sym != nil {
+		if sym.scope == s {
+			sym.scope = nil
+		}
+		delete(s.elems, name)
+		s.names = slices.DeleteFunc(s.names, func(n string) bool { return n == name })
+	}
+}
+
+// Shadows reports whether a symbol with the provided name in s would shadow a
+// symbol of an enclosing scope, and returns the symbol that would be shadowed.
+func (s *Scope) Shadows(name string) (*Symbol, bool) {
+	if s.parent == nil {
+		return nil, false
+	}
+	_, sym := s.parent.LookupParent(name)
+	return sym, sym != nil
+}
+
 func (s *Scope) Contains(pos src.Pos) bool {
 	return s.pos.Known() && s.end.Known() && !pos.Before(s.pos) && !pos.After(s.end)
 }
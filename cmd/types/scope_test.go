@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/src"
+	"slices"
+	"testing"
+)
+
+func TestUniverseParent(t *testing.T) {
+	mod := NewModule("test", "test/universe")
+	if sym := mod.Lookup("bool"); sym != nil {
+		t.Errorf("bool declared in the module scope")
+	}
+	s, sym := mod.scope.LookupParent("bool")
+	if s != Universe || sym == nil {
+		t.Fatalf("bool not found in the universe through the module scope")
+	}
+	if got := sym.extra.(Value).String(); got != "bool" {
+		t.Errorf("got %s, want bool", got)
+	}
+
+	// module declarations shadow the universe
+	wantErrors(t, "type int32 bool; var x: int32 = true;")
+}
+
+func TestScopeDelete(t *testing.T) {
+	s := NewScope(nil, src.NoPos, src.NoPos)
+	x, y := &Symbol{name: "x"}, &Symbol{name: "y"}
+	s.Insert(x)
+	s.Insert(y)
+	s.Delete("x")
+	s.Delete("z") // not declared
+	if s.Lookup("x") != nil || x.scope != nil {
+		t.Errorf("x still declared after Delete")
+	}
+	if s.Lookup("y") != y {
+		t.Errorf("y lost after deleting x")
+	}
+	if alt := s.Insert(&Symbol{name: "x"}); alt != nil {
+		t.Errorf("x cannot be declared again after Delete")
+	}
+}
+
+func TestShadows(t *testing.T) {
+	outer := NewScope(nil, src.NoPos, src.NoPos)
+	middle := NewScope(outer, src.NoPos, src.NoPos)
+	inner := NewScope(middle, src.NoPos, src.NoPos)
+	x := &Symbol{name: "x"}
+	outer.Insert(x)
+	middle.Insert(&Symbol{name: "y"})
+
+	if sym, ok := inner.Shadows("x"); !ok || sym != x {
+		t.Errorf("x in the inner scope does not shadow the outer x")
+	}
+	if _, ok := inner.Shadows("y"); !ok {
+		t.Errorf("y in the inner scope does not shadow the middle y")
+	}
+	if _, ok := middle.Shadows("y"); ok {
+		t.Errorf("y shadows itself")
+	}
+	if _, ok := outer.Shadows("x"); ok {
+		t.Errorf("a scope without parent shadows x")
+	}
+}
+
+func TestShadowWarning(t *testing.T) {
+	const decls = "var x: int32; const f = proc() { var x: int32; x = x; };"
+
+	// shadowing is mostly deliberate, so warnings are opt-in
+	wantWarnings(t, decls)
+
+	if err := base.EnableWarnings(base.WarnShadow); err != nil {
+		t.Fatal(err)
+	}
+	defer base.SuppressWarnings(base.WarnShadow)
+	wantWarnings(t, decls, "1:38: declaration of x shadows declaration at test.co:1:5")
+	wantWarnings(t, "const f = proc() { var bool: int32; bool = bool; };") // builtins are shadowed deliberately
+}
+
+func TestUniverseOrder(t *testing.T) {
+	// builtins are listed in declaration order, for tools to list them stably
+	want := []string{
+		"type", "void", "bool",
+		"int8", "int16", "int32", "int64", "intptr",
+		"uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64",
+		"false", "true",
+		"typeof", "sizeof",
+	}
+	if got := Universe.Names(); !slices.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	s := NewScope(nil, src.NoPos, src.NoPos)
+	for _, name := range []string{"c", "a", "b"} {
+		s.Insert(&Symbol{name: name})
+	}
+	s.Delete("a")
+	if got, want := s.Names(), []string{"c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
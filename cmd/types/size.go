@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the computation of type sizes and alignments.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/debug"
+)
+
+// Size returns the size of t in bytes.
+func (t *Type) Size() int64 {
+	CalcSize(t)
+	return int64(t.width)
+}
+
+// Alignment returns the alignment of t in bytes.
+func (t *Type) Alignment() int64 {
+	CalcSize(t)
+	return int64(t.align)
+}
+
+// Sizeable reports whether t has an in-memory representation, and thus a
+// size. Types of types, untyped constants and the invalid type have none, and
+// neither do the types containing them by value, such as a struct with a field
+// of invalid type.
+func Sizeable(t *Type) bool {
+	switch t.kind {
+	case TUNDEF, TTYPE, TUNTYPEDBOOL, TUNTYPEDINT, TUNTYPEDFLOAT:
+		return false
+	case TARRAY, TOPTION:
+		return Sizeable(t.Elem())
+	case TSTRUCT:
+		for _, f := range t.extra.(*Struct).Fields {
+			if !Sizeable(f.Type) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CalcSize computes the width and alignment of t, unless they are known
+// already. For option types, it also lays out the underlying structure.
+func CalcSize(t *Type) {
+	if t.align > 0 {
+		return
+	}
+	if !Sizeable(t) {
+		base.Fatalf("types: CalcSize of %v", t)
+	}
+
+	var width int64
+	var align int64 = 1
+
+	switch t.kind {
+	case TVOID:
+		width = 0
+
+	case TBOOL, TINT8, TUINT8:
+		width, align = 1, 1
+
+	case TINT16, TUINT16:
+		width, align = 2, 2
+
+	case TINT32, TUINT32, TFLOAT32:
+		width, align = 4, 4
+
+	case TINT64, TUINT64, TFLOAT64:
+		width, align = 8, 8
+
+	case TINTPTR, TUINTPTR, TPOINTER, TPROC:
+		width, align = int64(PtrSize), int64(PtrSize)
+
+	case TOPTION:
+		opt := t.extra.(*Option)
+		if hasNiche(opt.Elem) {
+			// the absent value is represented by the nil pointer, so no
+			// presence flag is needed
+			opt.Under = nil
+			width, align = opt.Elem.Size(), opt.Elem.Alignment()
+			break
+		}
+		if opt.Under == nil {
+			opt.Under = NewStruct([]*Field{
+				{Name: "value", Type: opt.Elem},
+				{Name: "present", Type: Types[TBOOL]},
+			})
+		}
+		width, align = opt.Under.Size(), opt.Under.Alignment()
+
+	case TARRAY:
+		a := t.extra.(*Array)
+		width = a.Elem.Size() * int64(a.Length)
+		align = a.Elem.Alignment()
+
+	case TSTRUCT:
+		for _, f := range t.extra.(*Struct).Fields {
+			a := f.Type.Alignment()
+			width = round(width, a) + f.Type.Size()
+			align = max(align, a)
+		}
+		width = round(width, align)
+
+	default:
+		base.Fatalf("types: CalcSize: unexpected type %v", t)
+	}
+
+	if width > maxWidth {
+		// the checker rejects such types, see fitsSize
+		base.Fatalf("types: type %v too large", t)
+	}
+	t.width = uint32(width)
+	t.align = uint8(align)
+
+	if t.kind == TOPTION && hasNiche(t.Elem()) {
+		debug.Assert(t.extra.(*Option).Under == nil, "niche option", t, "has a presence flag")
+		debug.Assert(t.width == t.Elem().width, "niche option", t, "differs in size from", t.Elem())
+	}
+}
+
+// maxWidth is the largest size in bytes a type may have.
+const maxWidth = 1<<32 - 1
+
+// fitsSize reports whether the size of the sizeable type t does not exceed
+// maxWidth, provided the types it is composed of do not either. The checker
+// uses it to reject types too large to be laid out, before computing their
+// size.
+func fitsSize(t *Type) bool {
+	switch t.kind {
+	case TARRAY:
+		a := t.extra.(*Array)
+		size := a.Elem.Size()
+		return size == 0 || int64(a.Length) <= maxWidth/size
+
+	case TOPTION:
+		elem := t.Elem()
+		return hasNiche(elem) || round(elem.Size()+1, elem.Alignment()) <= maxWidth
+
+	case TSTRUCT:
+		var width, align int64 = 0, 1
+		for _, f := range t.extra.(*Struct).Fields {
+			a := f.Type.Alignment()
+			width = round(width, a) + f.Type.Size()
+			align = max(align, a)
+		}
+		return round(width, align) <= maxWidth
+	}
+	return true
+}
+
+// hasNiche reports whether the values of t leave a bit pattern unused which
+// an option of t may use to represent the absent value. Pointers are never
+// nil, so an option of a pointer uses the nil pointer instead.
+func hasNiche(t *Type) bool {
+	return t.kind == TPOINTER
+}
+
+// round rounds o up to a multiple of r, where r must be a power of 2.
+func round(o, r int64) int64 {
+	return (o + r - 1) &^ (r - 1)
+}
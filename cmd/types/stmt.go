@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the type-checking of procedure bodies and statements.
+
+package types
+
+import (
+	"cobalt/base"
+	"cobalt/src"
+	"cobalt/syntax"
+	"slices"
+)
+
+// procBody type-checks the body of proc. An empty body is valid, but if the
+// procedure has a result, the body must end in a terminating statement.
+func (check *checker) procBody(proc *Proc) {
+	defer func(scope *Scope, p *Proc, labels map[string]*syntax.LabeledStmt) {
+		check.scope, check.proc, check.labels = scope, p, labels
+	}(check.scope, check.proc, check.labels)
+	check.scope, check.proc = proc.body, proc
+
+	// procedures are pure until proven otherwise
+	proc.flags |= procPure
+	check.procs = append(check.procs, proc)
+
+	// labels are visible throughout the procedure body, so they are
+	// collected before checking any statements
+	check.labels = make(map[string]*syntax.LabeledStmt)
+	check.collectLabels(proc.code.StmtList)
+
+	check.stmtList(proc.code.StmtList)
+	check.usage(proc.body)
+
+	sig := proc.typ.extra.(*Signature)
+	if !isVoid(sig.Result) && !isTerminating(proc.code) {
+		check.errorf(proc.code.Closing, "missing return")
+	}
+}
+
+// usage reports the symbols of the local scope s which are never used.
+func (check *checker) usage(s *Scope) {
+	var unused []*Symbol
+	for _, sym := range s.elems {
+		if sym.flags&symUsed == 0 {
+			unused = append(unused, sym)
+		}
+	}
+	slices.SortFunc(unused, func(a, b *Symbol) int {
+		return src.Compare(a.pos, b.pos)
+	})
+	for _, sym := range unused {
+		if sym.flags&symStatic == 0 {
+			check.errorf(sym.pos, "%s declared but not used", sym.name)
+		} else {
+			check.warnf(sym.pos, base.WarnUnused, "%s declared but not used", sym.name)
+		}
+	}
+}
+
+func (check *checker) stmtList(list []syntax.Stmt) {
+	for _, s := range list {
+		check.stmt(s)
+	}
+}
+
+func (check *checker) stmt(s syntax.Stmt) {
+	switch s := s.(type) {
+	case *syntax.BlockStmt:
+		check.openScope(s.Pos(), s.Closing)
+		check.stmtList(s.StmtList)
+		check.usage(check.scope)
+		check.closeScope()
+
+	case *syntax.IncDecStmt:
+		check.incDec(s.Pos(), s.Op, s.X)
+
+	case *syntax.ExprStmt:
+		var x operand
+		check.rawExpr(&x, s.X, nil)
+		if x.mode == invalid || x.mode == novalue {
+			break
+		}
+		if call, ok := s.X.(*syntax.CallExpr); ok && x.mode != typexpr && !check.isCast(call) {
+			break
+		}
+		check.errorf(s.Pos(), "%s is not used", &x)
+
+	case *syntax.DeclStmt:
+		check.declStmt(s.D)
+
+	case *syntax.AssignStmt:
+		check.assignStmt(s)
+
+	case *syntax.ReturnStmt:
+		check.returnStmt(s)
+
+	case *syntax.DeferStmt:
+		var x operand
+		check.rawExpr(&x, s.Call, nil)
+		if x.mode == typexpr || x.mode == constant {
+			check.errorf(s.Call.Pos(), "defer discards result of %s", &x)
+		}
+
+	case *syntax.LabeledStmt:
+		check.stmt(s.Stmt)
+
+	case *syntax.GotoStmt:
+		if check.labels[s.Label.Value] == nil {
+			check.errorf(s.Label.Pos(), "label %s not declared", s.Label.Value)
+		}
+
+	default:
+		base.Fatalf("types: unexpected statement %T", s)
+	}
+}
+
+// collectLabels records the labels in list, including those in nested blocks,
+// in check.labels. Labels must be unique within a procedure.
+func (check *checker) collectLabels(list []syntax.Stmt) {
+	for _, s := range list {
+		for {
+			l, ok := s.(*syntax.LabeledStmt)
+			if !ok {
+				break
+			}
+			name := l.Label.Value
+			if alt := check.labels[name]; alt != nil {
+				check.errorf(l.Label.Pos(), "label %s already declared (previous declaration at %s)", name, alt.Pos())
+			} else {
+				check.labels[name] = l
+			}
+			s = l.Stmt
+		}
+
+		if b, ok := s.(*syntax.BlockStmt); ok {
+			check.collectLabels(b.StmtList)
+		}
+	}
+}
+
+func (check *checker) assignStmt(s *syntax.AssignStmt) {
+	lhs := syntax.UnpackList(s.Lhs)
+	rhs := syntax.UnpackList(s.Rhs)
+	if len(lhs) != len(rhs) {
+		// _ cannot be evaluated, but is fine on the left-hand side
+		check.useExprs(slices.DeleteFunc(slices.Clone(lhs), isBlank))
+		r := len(rhs)
+		if _, ok := s.Rhs.(*syntax.CallExpr); ok {
+			var x operand
+			check.rawExpr(&x, s.Rhs, nil)
+			if x.mode == invalid {
+				return
+			}
+			r = resultCount(&x)
+		} else {
+			check.useExprs(rhs)
+		}
+		check.assignError(rhs, len(lhs), r)
+		return
+	}
+
+	for i, e := range lhs {
+		var x, y operand
+		if isBlank(e) && s.Op == 0 {
+			// assignments to _ discard the value, which must still
+			// have a type
+			check.expr(&y, rhs[i])
+			if y.mode != invalid {
+				check.defaultType(&y)
+			}
+			continue
+		}
+		check.lhsVar(&x, e, s.Op == 0)
+		if x.mode == invalid {
+			check.useExprs(rhs[i : i+1])
+			continue
+		}
+		if s.Op != 0 {
+			// x op= y is checked as x = x op y
+			op := syntax.Synthesized(s.Pos(), &syntax.Operation{Op: s.Op, Lhs: e, Rhs: rhs[i]})
+			check.expr(&y, op)
+		} else {
+			check.exprWithHint(&y, rhs[i], x.typ)
+		}
+		check.assignment(&y, x.typ, "assignment")
+	}
+}
+
+// lhsVar type-checks the left-hand side e of an assignment, which must be a
+// variable. Unless the assignment reads e as well, assigning to a variable
+// does not use it.
+func (check *checker) lhsVar(x *operand, e syntax.Expr, write bool) {
+	var sym *Symbol
+	if n, ok := e.(*syntax.Name); ok && write {
+		if _, sym = check.scope.LookupParent(n.Value); sym != nil && sym.flags&symUsed != 0 {
+			sym = nil // used already
+		}
+	}
+	check.expr(x, e)
+	if sym != nil {
+		sym.flags &^= symUsed
+	}
+
+	switch {
+	case x.mode == invalid:
+		return
+	case x.mode == variable:
+		check.recordWrite(e)
+		return
+	case isDeref(e):
+		check.errorf(e.Pos(), "cannot assign through const pointer")
+	default:
+		if sym := check.constSym(e); sym != nil {
+			check.errorf(e.Pos(), "cannot assign to constant %s", sym.name)
+			break
+		}
+		check.errorf(e.Pos(), "cannot assign to %s", x)
+	}
+	x.mode = invalid
+}
+
+// constSym returns the symbol e refers to if e is the name of a constant, such
+// as a constant procedure or a const parameter, and nil otherwise. e must have
+// been checked already.
+func (check *checker) constSym(e syntax.Expr) *Symbol {
+	if n, ok := e.(*syntax.Name); ok {
+		if sym := check.mod.names[n]; sym != nil && sym.flags&symConst != 0 {
+			return sym
+		}
+	}
+	return nil
+}
+
+// isDeref reports whether e is a pointer dereference. If such an expression
+// is not a variable, the pointer is a pointer-to-const.
+func isDeref(e syntax.Expr) bool {
+	op, ok := e.(*syntax.Operation)
+	return ok && op.Op == syntax.Deref
+}
+
+// isBlank reports whether e is the blank identifier _.
+func isBlank(e syntax.Expr) bool {
+	n, ok := e.(*syntax.Name)
+	return ok && n.Value == "_"
+}
+
+func (check *checker) returnStmt(s *syntax.ReturnStmt) {
+	sig := check.proc.typ.extra.(*Signature)
+
+	if s.Result == nil {
+		if !isVoid(sig.Result) {
+			check.errorf(s.Pos(), "not enough return values (want %s)", sig.Result)
+		}
+		return
+	}
+
+	if isVoid(sig.Result) {
+		check.errorf(s.Result.Pos(), "too many return values")
+		check.useExprs([]syntax.Expr{s.Result})
+		return
+	}
+
+	var x operand
+	check.exprWithHint(&x, s.Result, sig.Result)
+	check.assignment(&x, sig.Result, "return statement")
+}
+
+// isTerminating reports whether s is a terminating statement, i.e. a
+// statement after which control never reaches the end of the enclosing block.
+func isTerminating(s syntax.Stmt) bool {
+	switch s := s.(type) {
+	case *syntax.ReturnStmt, *syntax.GotoStmt:
+		return true
+	case *syntax.LabeledStmt:
+		return isTerminating(s.Stmt)
+	case *syntax.BlockStmt:
+		n := len(s.StmtList)
+		return n > 0 && isTerminating(s.StmtList[n-1])
+	}
+	return false
+}
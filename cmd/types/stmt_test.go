@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestEmptyProcBody(t *testing.T) {
+	wantErrors(t, "const f = proc() {};")
+	wantErrors(t, "const f = proc() void {};")
+	wantErrors(t, "const f = proc(x: int32) {};")
+	wantErrors(t, "const f = proc() int32 {};", "1:25: missing return")
+}
+
+func TestAssignability(t *testing.T) {
+	wantErrors(t, "var v: int32; var p: *int32; const f = proc() { v = 1; p.* = v; };")
+	wantErrors(t, "var v: int32; const f = proc() { 1 = v; };", "1:34: cannot assign to untyped int constant 1")
+	wantErrors(t, "var v: int32; const g = proc() int32 { return v; }; const f = proc() { g() = v; };", "1:73: cannot assign to value of type int32")
+	wantErrors(t, "var v: int32; const f = proc() { (int8)v = 2; };", "1:34: cannot assign to value of type int8")
+}
+
+func TestReturnStmt(t *testing.T) {
+	wantErrors(t, "const f = proc() { return; };")
+	wantErrors(t, "const f = proc() int32 { return 1; };")
+	wantErrors(t, "const f = proc() int32 { return; };", "1:26: not enough return values (want int32)")
+	wantErrors(t, "const f = proc() { return 1; };", "1:27: too many return values")
+	wantErrors(t, "const f = proc() int32 { return true; };", "1:33: cannot use true (untyped bool constant true) as int32 value in return statement")
+}
+
+func TestAssignmentCounts(t *testing.T) {
+	const f = "const f = proc() { var a, b: int32; "
+	wantErrors(t, f+"a, b = 1, 2; a, b = b, a; a = a + b; };")
+	wantErrors(t, f+"a, b = 1; a = a + b; };", "1:44: assignment mismatch: 2 variables but 1 value")
+	wantErrors(t, f+"a, b = 1, 2, 3; a = a + b; };", "1:44: assignment mismatch: 2 variables but 3 values")
+	wantErrors(t, "var a, b: int32 = 1, 2, 3;", "1:19: assignment mismatch: 2 variables but 3 values")
+}
+
+func TestResultCountMismatch(t *testing.T) {
+	const g = "const g = proc() int32 { return 1; }; const h = proc() {}; var x, y: int32; "
+	wantErrors(t, g+"const f = proc() { x = g(); };")
+	wantErrors(t, g+"const f = proc() { x, y = g(); };", "1:104: g returns 1 value but 2 variables")
+	wantErrors(t, g+"const f = proc() { x, y = h(); };", "1:104: h returns 0 values but 2 variables")
+	wantErrors(t, g+"var a, b = g();", "1:89: g returns 1 value but 2 variables")
+	wantErrors(t, g+"const f = proc() { var a, b = g(); };", "1:108: g returns 1 value but 2 variables",
+		"1:100: a declared but not used", "1:103: b declared but not used")
+}
+
+func TestCompoundAssignmentPos(t *testing.T) {
+	// x op= y is checked as the synthesized operation x op y
+	wantErrors(t, "const f = proc() { var x: int32; x += true; x = x; };", "1:34: invalid operation: int32 + untyped bool (mismatched types)")
+	wantErrors(t, "const f = proc() { var x: int32; x <<= 1; x = x; };")
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	const vars = "const f = proc() { var x: int32; var y: float64; var b: bool; "
+	wantErrors(t, vars+"x += 1; x *= x; x <<= 2; y /= 2; y -= 0.5; b = b; x = x; y = y; };")
+	wantErrors(t, vars+"b += true; b = b; x = x; y = y; };", "1:63: invalid operation: operator + not defined on b (variable of type bool)")
+	wantErrors(t, vars+"y %= 2.0; b = b; x = x; y = y; };", "1:63: invalid operation: operator % not defined on y (variable of type float64)")
+	wantErrors(t, vars+"x += 1.5; b = b; x = x; y = y; };", "1:68: constant 1.5 truncated to int32")
+	wantErrors(t, vars+"y <<= 1; b = b; x = x; y = y; };", "1:63: invalid operation: shifted operand y (variable of type float64) must be integral")
+}
+
+func TestConstAssignment(t *testing.T) {
+	const decls = "const c = 1; var v: int32; "
+	wantErrors(t, decls+"const f = proc() { v = 5; var l: int32; l = v; v = l; };")
+	wantErrors(t, decls+"const f = proc() { c = 5; };", "1:47: cannot assign to constant c")
+	wantErrors(t, "const g = proc(const p: int32) { p = 1; };", "1:34: cannot assign to constant p")
+	wantErrors(t, "const h = proc() { const l: int32 = 1; l = 2; };", "1:40: cannot assign to constant l")
+}
+
+func TestLabels(t *testing.T) {
+	wantErrors(t, "const f = proc() { var x: int32; loop: x = x + 1; goto loop; };")
+	wantErrors(t, "const f = proc() { goto end; { end: return; } };")
+	wantErrors(t, "const f = proc() { goto loop; };", "1:25: label loop not declared")
+	wantErrors(t, "const f = proc() { a: return; { a: return; } };", "1:33: label a already declared (previous declaration at test.co:1:20)")
+}
+
+func TestIncDec(t *testing.T) {
+	wantErrors(t, "const f = proc() { var x: int32; x++; --x; var p: *float32; p.*++; };")
+	wantErrors(t, "const f = proc() { var x, a: int32; a = a + x++; };", "1:46: increment used in expression context")
+	wantErrors(t, "const f = proc() { var x: int32; var a = -x--; a = a; };", "1:44: decrement used in expression context")
+	wantErrors(t, "const f = proc() { var b: bool; b++; };", "1:34: invalid operation: ++b (variable of type bool) (non-numeric type bool)")
+	wantErrors(t, "const f = proc() { const c: int32 = 1; c++; };", "1:41: invalid operation: cannot increment constant c")
+	wantErrors(t, "const f = proc() { 1++; };", "1:21: invalid operation: cannot increment untyped int constant 1")
+}
+
+func TestDeferStmt(t *testing.T) {
+	wantErrors(t, "const g = proc(x: int32) int32 { return x; }; const f = proc() { defer g(1); };")
+	wantErrors(t, "const f = proc() { defer h(); };", "1:26: undefined: h")
+	wantErrors(t, "const f = proc() { defer sizeof(int32); };", "1:32: defer discards result of constant 4 of type uintptr")
+}
+
+func TestUnusedLocals(t *testing.T) {
+	// unused variables are errors, unused constants and types only warnings
+	wantErrors(t, "const f = proc() { var x: int32; };", "1:24: x declared but not used")
+	wantErrors(t, "const f = proc() { var x: int32; { var y = x; } };", "1:40: y declared but not used")
+	wantErrors(t, "const f = proc() int32 { var x: int32 = 1; return x; };")
+	wantErrors(t, "var g: int32; const f = proc() { g = 1; };")
+	wantWarnings(t, "const f = proc() { const c = 1; type T int32; };", "1:26: c declared but not used", "1:38: T declared but not used")
+	wantErrors(t, "const f = proc() { const c = 1; type T int32; };")
+}
+
+func TestConstPointerWrites(t *testing.T) {
+	const f = "const f = proc(p: *int32, q: *const int32) { "
+	wantErrors(t, f+"p.* = 1; p.* += 1; p.*++; var a = q.*; p.* = a; };")
+	wantErrors(t, f+"q.* = 2; };", "1:47: cannot assign through const pointer")
+	wantErrors(t, f+"q.* += 1; };", "1:47: cannot assign through const pointer")
+	wantErrors(t, f+"q.*++; };", "1:49: invalid operation: cannot increment through const pointer")
+
+	// a const pointer does not convert back implicitly
+	wantErrors(t, f+"var r: *int32 = q; r.* = 1; };", "1:62: cannot use q (variable of type *const int32) as *int32 value in declaration")
+	wantErrors(t, f+"var r: *const int32 = p; p.* = r.*; };")
+	wantErrors(t, f+"var r = (*int32)q; r.* = 1; };", "1:62: cannot convert q (variable of type *const int32) to type *int32")
+	wantErrors(t, f+"var r = (*const int32)p; var s = (*const int8)q; p.* = r.* + (int32)(s.*); };")
+}
@@ -34,6 +34,11 @@ const (
 	TFLOAT32
 	TFLOAT64
 
+	// untyped constants
+	TUNTYPEDBOOL
+	TUNTYPEDINT
+	TUNTYPEDFLOAT
+
 	NBASIC
 
 	TPOINTER
@@ -47,11 +52,22 @@ const (
 
 func (k Kind) IsBasic() bool    { return k != TUNDEF && k < NBASIC }
 func (k Kind) IsCompound() bool { return k > NBASIC && k < NTYPES }
+func (k Kind) IsUntyped() bool  { return k >= TUNTYPEDBOOL && k <= TUNTYPEDFLOAT }
 func (k Kind) IsSigned() bool   { return k >= TINT8 && k <= TINTPTR }
 func (k Kind) IsUnsigned() bool { return k >= TUINT8 && k <= TUINTPTR }
-func (k Kind) IsIntegral() bool { return k >= TINT8 && k <= TUINTPTR }
-func (k Kind) IsFloat() bool    { return k == TFLOAT32 || k == TFLOAT64 }
-func (k Kind) IsNumeric() bool  { return k >= TINT8 && k <= TFLOAT64 }
+func (k Kind) IsIntegral() bool { return k >= TINT8 && k <= TUINTPTR || k == TUNTYPEDINT }
+func (k Kind) IsFloat() bool    { return k == TFLOAT32 || k == TFLOAT64 || k == TUNTYPEDFLOAT }
+func (k Kind) IsNumeric() bool  { return k.IsIntegral() || k.IsFloat() }
+
+// IsOrdered reports whether values of kind k may be ordered using <, <=, >
+// and >=. As there are no strings yet, only numeric kinds are ordered.
+func (k Kind) IsOrdered() bool { return k.IsNumeric() }
+
+// IsComparable reports whether values of kind k may be compared using == and
+// !=. Besides the ordered kinds, these are booleans, pointers and types.
+func (k Kind) IsComparable() bool {
+	return k.IsOrdered() || k == TBOOL || k == TUNTYPEDBOOL || k == TPOINTER || k == TTYPE
+}
 
 // Type represents a Cobalt type, which describes the set of permitted values
 // and the in-memory representation of the type.
@@ -64,6 +80,8 @@ type Type struct {
 	//  TSTRUCT: *Struct
 	extra any
 
+	str string // memoized result of String, or ""
+
 	// only valid once align > 0
 	width uint32
 	align uint8
@@ -104,6 +122,21 @@ func (t *Type) Elem() *Type {
 	return nil
 }
 
+// Field returns the field of the struct type t with the provided name, along
+// with its index. If t is not a struct type or has no such field, Field
+// returns nil and -1.
+func (t *Type) Field(name string) (*Field, int) {
+	if t.kind != TSTRUCT {
+		return nil, -1
+	}
+	for i, f := range t.extra.(*Struct).Fields {
+		if f.Name == name {
+			return f, i
+		}
+	}
+	return nil, -1
+}
+
 // Pointer contains additional Type fields for pointer types.
 type Pointer struct {
 	Elem  *Type
@@ -112,8 +145,11 @@ type Pointer struct {
 
 // Option contains additional Type fields for option types.
 type Option struct {
-	Elem  *Type
-	Under *Type // underlying structure
+	Elem *Type
+
+	// Under is the underlying structure struct{value: Elem; present: bool}.
+	// It is built by CalcSize, unless Elem has a niche to represent none.
+	Under *Type
 }
 
 // Array contains additional Type fields for array types.
@@ -125,7 +161,7 @@ type Array struct {
 // Signature contains additional Type fields for procedure types.
 type Signature struct {
 	Params []*Field
-	Result *Type
+	Result *Type // never nil, void for procedures without result
 }
 
 // Struct contains additional Type fields for struct types.
@@ -164,7 +200,13 @@ func NewArray(elem *Type, length int32) *Type {
 	}
 }
 
+// NewSignature returns a new procedure type. A nil result denotes a procedure
+// without result and is normalized to void, such that the result of a
+// signature is never nil.
 func NewSignature(params []*Field, result *Type) *Type {
+	if result == nil {
+		result = Types[TVOID]
+	}
 	return &Type{
 		extra: &Signature{params, result},
 		kind:  TPROC,
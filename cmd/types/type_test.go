@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestKindCategories(t *testing.T) {
+	for _, test := range []struct {
+		k                   Kind
+		ordered, comparable bool
+	}{
+		{TUNDEF, false, false},
+		{TTYPE, false, true},
+		{TVOID, false, false},
+		{TBOOL, false, true},
+		{TINT8, true, true},
+		{TINT16, true, true},
+		{TINT32, true, true},
+		{TINT64, true, true},
+		{TINTPTR, true, true},
+		{TUINT8, true, true},
+		{TUINT16, true, true},
+		{TUINT32, true, true},
+		{TUINT64, true, true},
+		{TUINTPTR, true, true},
+		{TFLOAT32, true, true},
+		{TFLOAT64, true, true},
+		{TUNTYPEDBOOL, false, true},
+		{TUNTYPEDINT, true, true},
+		{TUNTYPEDFLOAT, true, true},
+		{TPOINTER, false, true},
+		{TOPTION, false, false},
+		{TARRAY, false, false},
+		{TPROC, false, false},
+		{TSTRUCT, false, false},
+	} {
+		if got := test.k.IsOrdered(); got != test.ordered {
+			t.Errorf("%v.IsOrdered() = %v, want %v", test.k, got, test.ordered)
+		}
+		if got := test.k.IsComparable(); got != test.comparable {
+			t.Errorf("%v.IsComparable() = %v, want %v", test.k, got, test.comparable)
+		}
+	}
+}
+
+func TestHashType(t *testing.T) {
+	i32, i64 := Types[TINT32], Types[TINT64]
+	point := func(y *Type) *Type {
+		return NewStruct([]*Field{{Name: "x", Type: i32}, {Name: "y", Type: y}})
+	}
+	same := [][2]*Type{
+		{NewPointer(i32, false), NewPointer(i32, false)},
+		{NewArray(i32, 4), NewArray(i32, 4)},
+		{point(i32), point(i32)},
+		// parameter names are ignored, as by Identical
+		{NewSignature([]*Field{{Name: "a", Type: i32}}, nil), NewSignature([]*Field{{Name: "b", Type: i32}}, Types[TVOID])},
+	}
+	for _, p := range same {
+		if hashType(p[0]) != hashType(p[1]) {
+			t.Errorf("%s and %s hash differently", p[0], p[1])
+		}
+	}
+
+	different := [][2]*Type{
+		{i32, i64},
+		{NewPointer(i32, false), NewPointer(i32, true)},
+		{NewArray(i32, 4), NewArray(i32, 5)},
+		{NewOption(i32), NewPointer(i32, false)},
+		{point(i32), point(i64)},
+		{NewStruct([]*Field{{Name: "x", Type: i32}}), NewStruct([]*Field{{Name: "y", Type: i32}})},
+		{NewSignature([]*Field{{Type: i32}}, nil), NewSignature(nil, i32)},
+	}
+	for _, p := range different {
+		if hashType(p[0]) == hashType(p[1]) {
+			t.Errorf("%s and %s hash equally", p[0], p[1])
+		}
+	}
+}
+
+func TestOptionLayout(t *testing.T) {
+	// a value option is laid out as struct{value: Elem; present: bool}
+	opt := NewOption(Types[TINT32])
+	if got := opt.Size(); got != 8 {
+		t.Errorf("sizeof(%s) = %d, want 8", opt, got)
+	}
+	under := opt.extra.(*Option).Under
+	if under == nil {
+		t.Fatalf("%s has no underlying structure", opt)
+	}
+	if got, want := under.String(), "struct{value: int32; present: bool}"; got != want {
+		t.Errorf("%s has underlying structure %s, want %s", opt, got, want)
+	}
+
+	// a pointer option uses the nil pointer for the absent value
+	ptr := NewOption(NewPointer(Types[TINT32], false))
+	if got := ptr.Size(); got != int64(PtrSize) {
+		t.Errorf("sizeof(%s) = %d, want %d", ptr, got, PtrSize)
+	}
+	if under := ptr.extra.(*Option).Under; under != nil {
+		t.Errorf("niche option %s has underlying structure %s", ptr, under)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// String returns a string representation of t using Cobalt's type syntax.
+// Named types, including the built-in types, are represented by their name.
+// The result is memoized, as types are immutable once constructed.
+func (t *Type) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	if t.str == "" {
+		w := typeWriter{seen: make(map[*Type]bool)}
+		w.typ(t)
+		t.str = w.b.String()
+	}
+	return t.str
+}
+
+// A typeWriter writes the string representation of types. It keeps track of
+// the types being written, such that a type referring to itself does not
+// cause infinite recursion. Types can only do so through named types, which
+// are written as their name, but seen guards against this nonetheless.
+type typeWriter struct {
+	b    strings.Builder
+	seen map[*Type]bool
+}
+
+func (w *typeWriter) typ(t *Type) {
+	b := &w.b
+	if t == nil {
+		b.WriteString("<nil>")
+		return
+	}
+
+	if t.sym != nil {
+		b.WriteString(t.sym.name)
+		return
+	}
+
+	if t.str != "" {
+		b.WriteString(t.str)
+		return
+	}
+
+	if w.seen[t] {
+		b.WriteString("...")
+		return
+	}
+	w.seen[t] = true
+	defer delete(w.seen, t)
+
+	switch t.kind {
+	case TUNDEF:
+		b.WriteString("invalid type")
+
+	case TUNTYPEDBOOL:
+		b.WriteString("untyped bool")
+
+	case TUNTYPEDINT:
+		b.WriteString("untyped int")
+
+	case TUNTYPEDFLOAT:
+		b.WriteString("untyped float")
+
+	case TPOINTER:
+		p := t.extra.(*Pointer)
+		b.WriteByte('*')
+		if p.Const {
+			b.WriteString("const ")
+		}
+		w.typ(p.Elem)
+
+	case TOPTION:
+		b.WriteByte('?')
+		w.typ(t.extra.(*Option).Elem)
+
+	case TARRAY:
+		a := t.extra.(*Array)
+		b.WriteByte('[')
+		if a.Length < 0 {
+			b.WriteByte('_') // inferred length, not known yet
+		} else {
+			b.WriteString(strconv.Itoa(int(a.Length)))
+		}
+		b.WriteByte(']')
+		w.typ(a.Elem)
+
+	case TPROC:
+		sig := t.extra.(*Signature)
+		b.WriteString("proc(")
+		for i, f := range sig.Params {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			w.field(f)
+		}
+		b.WriteByte(')')
+		if !isVoid(sig.Result) {
+			b.WriteByte(' ')
+			w.typ(sig.Result)
+		}
+
+	case TSTRUCT:
+		b.WriteString("struct{")
+		for i, f := range t.extra.(*Struct).Fields {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			w.field(f)
+		}
+		b.WriteByte('}')
+
+	default:
+		b.WriteString("<unknown type>")
+	}
+}
+
+func (w *typeWriter) field(f *Field) {
+	b := &w.b
+	if f.Const {
+		b.WriteString("const ")
+	}
+	if f.Name != "" {
+		b.WriteString(f.Name)
+		b.WriteString(": ")
+	}
+	w.typ(f.Type)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import "testing"
+
+func TestTypeString(t *testing.T) {
+	i32 := Types[TINT32]
+	for _, test := range []struct {
+		typ  *Type
+		want string
+	}{
+		{i32, "int32"},
+		{Types[TUNDEF], "invalid type"},
+		{NewPointer(i32, false), "*int32"},
+		{NewPointer(i32, true), "*const int32"},
+		{NewOption(NewPointer(i32, false)), "?*int32"},
+		{NewArray(NewArray(i32, 2), 3), "[3][2]int32"},
+		{NewSignature(nil, nil), "proc()"},
+		{NewSignature([]*Field{{Name: "a", Type: i32}, {Type: Types[TBOOL], Const: true}}, i32), "proc(a: int32, const bool) int32"},
+		{NewStruct([]*Field{{Name: "x", Type: i32}, {Name: "y", Type: i32, Const: true}}), "struct{x: int32; const y: int32}"},
+	} {
+		if got := test.typ.String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestRecursiveTypeString(t *testing.T) {
+	mod := wantErrors(t, "type Node struct{next: *Node; val: int32;}; var n: Node; var p: ?*Node;")
+	named := mod.Lookup("Node").extra.(typeValue).t
+	for _, test := range []struct {
+		typ  *Type
+		want string
+	}{
+		{named, "Node"},
+		{mod.Lookup("p").typ, "?*Node"},
+		{NewStruct(named.extra.(*Struct).Fields), "struct{next: *Node; val: int32}"},
+	} {
+		if got := test.typ.String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+
+	// a type can only refer to itself through a named type, but an unnamed
+	// cycle is still written without recursing infinitely
+	s := NewStruct(nil)
+	s.extra.(*Struct).Fields = []*Field{{Name: "next", Type: NewPointer(s, false)}}
+	if got, want := s.String(), "struct{next: *...}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if s.str != "struct{next: *...}" {
+		t.Errorf("got memoized string %q, want %q", s.str, "struct{next: *...}")
+	}
+}
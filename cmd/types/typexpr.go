@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+// This file implements the type-checking of type expressions.
+
+package types
+
+import (
+	"cobalt/syntax"
+	"math"
+)
+
+// typ type-checks the type expression e and returns its type. If e is not a
+// valid type, an error is reported and the invalid type is returned.
+func (check *checker) typ(e syntax.Expr) *Type {
+	var x operand
+	check.rawExpr(&x, e, nil)
+
+	switch x.mode {
+	case invalid:
+		return Types[TUNDEF]
+	case typexpr:
+		return x.typ
+	}
+
+	check.errorf(e.Pos(), "%s is not a type", &x)
+	return Types[TUNDEF]
+}
+
+// varType type-checks the type expression e like typ, but additionally
+// requires it to be a type values can have. This excludes void, which is only
+// valid as a procedure result.
+func (check *checker) varType(e syntax.Expr) *Type {
+	T := check.typ(e)
+	if T.kind == TVOID {
+		check.errorf(e.Pos(), "void is not a value type")
+		return Types[TUNDEF]
+	}
+	return T
+}
+
+// typExpr returns the type denoted by a type literal.
+func (check *checker) typExpr(e syntax.Expr) *Type {
+	T := check.typLit(e)
+	if Sizeable(T) && !fitsSize(T) {
+		check.errorf(e.Pos(), "type %s too large", T)
+		return Types[TUNDEF]
+	}
+	return T
+}
+
+// typLit returns the type denoted by a type literal, without checking its
+// size.
+func (check *checker) typLit(e syntax.Expr) *Type {
+	switch e := e.(type) {
+	case *syntax.PointerType:
+		return NewPointer(check.typ(e.Elem), e.Const)
+
+	case *syntax.OptionType:
+		return NewOption(check.varType(e.Elem))
+
+	case *syntax.ArrayType:
+		if isBlank(e.Len) {
+			check.errorf(e.Len.Pos(), "invalid use of [_] array outside of a compound literal")
+			check.varType(e.Elem)
+			return Types[TUNDEF]
+		}
+		elem := check.varType(e.Elem)
+		n := check.arrayLength(e.Len)
+		if n < 0 {
+			return Types[TUNDEF]
+		}
+		return NewArray(elem, n)
+
+	case *syntax.ProcType:
+		return check.procType(e)
+
+	case *syntax.StructType:
+		return check.structType(e)
+	}
+
+	check.errorf(e.Pos(), "unexpected type expression %T", e)
+	return Types[TUNDEF]
+}
+
+// litType type-checks the type expression e of a compound literal and returns
+// its type. Unlike other type expressions, e may be an array type [_]T, whose
+// length is inferred from the literal by [checker.arrayLit].
+func (check *checker) litType(e syntax.Expr) *Type {
+	if a, ok := e.(*syntax.ArrayType); ok && isBlank(a.Len) {
+		return &Type{extra: &Array{check.varType(a.Elem), -1}, kind: TARRAY}
+	}
+	return check.typ(e)
+}
+
+// arrayLength returns the length of an array type, or a negative value if
+// the length is not a valid constant.
+func (check *checker) arrayLength(e syntax.Expr) int32 {
+	var x operand
+	check.expr(&x, e)
+	if x.mode == invalid {
+		return -1
+	}
+
+	if x.mode != constant || !isIntegral(x.typ) {
+		check.errorf(e.Pos(), "array length %s must be a constant integer", &x)
+		return -1
+	}
+
+	n, ok := int64Val(x.val)
+	if !ok || n < 0 || n > math.MaxInt32 {
+		check.errorf(e.Pos(), "invalid array length %s", x.val)
+		return -1
+	}
+
+	return int32(n)
+}
+
+func (check *checker) procType(e *syntax.ProcType) *Type {
+	params := make([]*Field, len(e.ParamList))
+	for i, f := range e.ParamList {
+		params[i] = check.field(f)
+	}
+
+	var result *Type
+	if e.Result != nil {
+		result = check.typ(e.Result)
+	}
+
+	return NewSignature(params, result)
+}
+
+func (check *checker) structType(e *syntax.StructType) *Type {
+	fields := make([]*Field, len(e.FieldList))
+	seen := make(map[string]bool, len(e.FieldList))
+	for i, f := range e.FieldList {
+		fields[i] = check.field(f)
+		if name := fields[i].Name; name != "_" && seen[name] {
+			check.errorf(f.Pos(), "duplicate field %s", name)
+		} else {
+			seen[name] = true
+		}
+	}
+
+	return NewStruct(fields)
+}
+
+func (check *checker) field(f *syntax.Field) *Field {
+	field := &Field{Type: check.varType(f.Type), Const: f.Const}
+	if f.Name != nil {
+		field.Name = f.Name.Value
+	}
+	return field
+}
@@ -28,6 +28,11 @@ func Init() {
 	}
 
 	Universe = NewScope(nil, src.NoPos, src.NoPos)
+	modmap = make(map[string]*Module)
+
+	// the invalid type has no name, and is not accessible from source
+	Types[TUNDEF] = &Type{kind: TUNDEF}
+
 	initTypes()
 	initConsts()
 	initBuiltins()
@@ -75,6 +80,11 @@ func initTypes() {
 	decl(TUINTPTR, "uintptr")
 	decl(TFLOAT32, "float32")
 	decl(TFLOAT64, "float64")
+
+	// the untyped types have no name, and are not accessible from source
+	Types[TUNTYPEDBOOL] = &Type{kind: TUNTYPEDBOOL}
+	Types[TUNTYPEDINT] = &Type{kind: TUNTYPEDINT}
+	Types[TUNTYPEDFLOAT] = &Type{kind: TUNTYPEDFLOAT}
 }
 
 func initConsts() {
@@ -87,8 +97,8 @@ func initConsts() {
 		debug.Assert(Universe.Insert(sym) == nil, "duplicate declaration of builtin", name)
 	}
 
-	decl(TBOOL, "false", MakeBool(false))
-	decl(TBOOL, "true", MakeBool(true))
+	decl(TUNTYPEDBOOL, "false", MakeBool(false))
+	decl(TUNTYPEDBOOL, "true", MakeBool(true))
 }
 
 func initBuiltins() {
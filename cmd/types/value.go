@@ -5,19 +5,30 @@
 package types
 
 import (
+	"cobalt/base"
 	"cobalt/syntax"
+	"fmt"
+	"maps"
 	"math"
+	"math/big"
+	"math/bits"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // Value is a value that is representable in a Cobalt program. It is to be used
 // for representing and evaluating static values. [Undefined] is to be used for
 // unknown/undefined values, not nil.
 //
-// For arithmetic operations, the result's Kind is promoted to a higher precision
-// if it does not fit within the original Kind. Signed and unsigned will always
-// remain the same, but just with a higher precision. Operations involving an
-// integral type with a floating-point type return a floating-point type.
+// Values are either typed, with a sized Kind such as TINT32, or untyped, with
+// one of the untyped Kinds. The Make functions return untyped values, which
+// only get a sized Kind, and are range-checked, once converted to one.
+//
+// The results of unary and binary operations are always untyped, regardless
+// of the Kinds of the operands. Operations involving an integral value with a
+// floating-point value return a floating-point value. If an integral result
+// does not fit in 64 bits, it is promoted to an arbitrary-precision integer.
 type Value interface {
 	Kind() Kind
 	String() string
@@ -62,7 +73,7 @@ func MakeType(t *Type) Value {
 }
 
 func (typeValue) Kind() Kind                          { return TTYPE }
-func (typeValue) String() string                      { return "<type>" } // TODO: implement type strings
+func (v typeValue) String() string                    { return v.t.String() }
 func (typeValue) Unary(syntax.Operator) Value         { return Undefined }
 func (typeValue) Binary(syntax.Operator, Value) Value { return Undefined }
 func (v typeValue) Convert(to Kind) Value {
@@ -73,15 +84,21 @@ func (v typeValue) Convert(to Kind) Value {
 }
 
 // boolValue is a boolean as a value
-type boolValue struct{ b bool }
+type boolValue struct {
+	b     bool
+	typed bool // TBOOL rather than TUNTYPEDBOOL
+}
 
-// MakeBool returns a Value with the provided boolean.
+// MakeBool returns an untyped boolean Value with the provided boolean.
 func MakeBool(b bool) Value {
-	return boolValue{b}
+	return boolValue{b, false}
 }
 
-func (boolValue) Kind() Kind {
-	return TBOOL
+func (v boolValue) Kind() Kind {
+	if v.typed {
+		return TBOOL
+	}
+	return TUNTYPEDBOOL
 }
 
 func (v boolValue) String() string {
@@ -115,9 +132,22 @@ func (v boolValue) Binary(op syntax.Operator, w Value) Value {
 	return Undefined
 }
 
+// Convert converts v to the desired Kind. Booleans convert to integers as 1
+// for true and 0 for false, but do not convert to floats. The reverse is not
+// permitted either: integers never convert to booleans, so that a condition
+// must always be spelled out, as in x != 0.
 func (v boolValue) Convert(to Kind) Value {
-	if to == v.Kind() {
-		return v
+	switch {
+	case to == TBOOL:
+		return boolValue{v.b, true}
+	case to == TUNTYPEDBOOL:
+		return boolValue{v.b, false}
+	case to.IsIntegral():
+		var x int64
+		if v.b {
+			x = 1
+		}
+		return MakeInt(x).Convert(to)
 	}
 	return Undefined
 }
@@ -125,23 +155,18 @@ func (v boolValue) Convert(to Kind) Value {
 // intValue is a signed integral value
 type intValue struct {
 	x    int64
-	bits int // 8, 16, 32 or 64
+	bits int // 8, 16, 32 or 64, or 0 if untyped
 }
 
-// MakeInt returns a signed integer Value with the provided integer.
-//
-// It defaults to a 32-bit integer, but uses a 64-bit integer if x does not fit
-// in just 32 bits.
+// MakeInt returns an untyped integer Value with the provided integer.
 func MakeInt(x int64) Value {
-	if x < -1<<31 || x > 1<<31-1 {
-		return intValue{x, 64}
-	} else {
-		return intValue{x, 32}
-	}
+	return intValue{x, 0}
 }
 
 func (v intValue) Kind() Kind {
 	switch v.bits {
+	case 0:
+		return TUNTYPEDINT
 	case 8:
 		return TINT8
 	case 16:
@@ -151,7 +176,17 @@ func (v intValue) Kind() Kind {
 	case 64:
 		return TINT64
 	}
-	panic("unreachable")
+	base.Fatalf("types: invalid bit width %d", v.bits)
+	return TUNDEF // unreachable
+}
+
+// width returns the number of bits used to represent v, which is 64 for
+// untyped values.
+func (v intValue) width() int {
+	if v.bits == 0 {
+		return 64
+	}
+	return v.bits
 }
 
 func (v intValue) String() string {
@@ -159,20 +194,24 @@ func (v intValue) String() string {
 }
 
 func (v intValue) Unary(op syntax.Operator) Value {
+	x, ok := v.x, true
 	switch op {
 	case syntax.Not: // ~v
-		v.x = ^v.x
+		x = ^v.x
 	case syntax.Inc: // ++v or v++
-		v.x += 1
+		x, ok = addInt64(v.x, 1)
 	case syntax.Dec: // --v or v--
-		v.x -= 1
+		x, ok = subInt64(v.x, 1)
 	case syntax.Add: // +v
 		// no-op
 	case syntax.Sub: // -v
-		v.x = -v.x
+		x, ok = subInt64(0, v.x)
 	}
 
-	return MakeInt(v.x)
+	if !ok {
+		return bigValue{big.NewInt(v.x)}.Unary(op)
+	}
+	return MakeInt(x)
 }
 
 func (v intValue) Binary(op syntax.Operator, w Value) Value {
@@ -276,9 +315,15 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 	case syntax.Add:
 		switch w := w.(type) {
 		case intValue:
-			return MakeInt(v.x + w.x)
+			if x, ok := addInt64(v.x, w.x); ok {
+				return MakeInt(x)
+			}
 		case uintValue:
-			return MakeInt(v.x + int64(w.x))
+			if y, ok := uintToInt64(w.x); ok {
+				if x, ok := addInt64(v.x, y); ok {
+					return MakeInt(x)
+				}
+			}
 		case floatValue:
 			return MakeFloat(float64(v.x) + w.x)
 		}
@@ -286,9 +331,15 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 	case syntax.Sub:
 		switch w := w.(type) {
 		case intValue:
-			return MakeInt(v.x - w.x)
+			if x, ok := subInt64(v.x, w.x); ok {
+				return MakeInt(x)
+			}
 		case uintValue:
-			return MakeInt(v.x - int64(w.x))
+			if y, ok := uintToInt64(w.x); ok {
+				if x, ok := subInt64(v.x, y); ok {
+					return MakeInt(x)
+				}
+			}
 		case floatValue:
 			return MakeFloat(float64(v.x) - w.x)
 		}
@@ -312,9 +363,15 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 	case syntax.Mul:
 		switch w := w.(type) {
 		case intValue:
-			return MakeInt(v.x * int64(w.x))
+			if x, ok := mulInt64(v.x, w.x); ok {
+				return MakeInt(x)
+			}
 		case uintValue:
-			return MakeInt(v.x * int64(w.x))
+			if y, ok := uintToInt64(w.x); ok {
+				if x, ok := mulInt64(v.x, y); ok {
+					return MakeInt(x)
+				}
+			}
 		case floatValue:
 			return MakeFloat(float64(v.x) * w.x)
 		}
@@ -325,12 +382,16 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 			if w.x == 0 {
 				return Undefined
 			}
-			return MakeInt(v.x / w.x)
+			if x, ok := divInt64(v.x, w.x); ok {
+				return MakeInt(x)
+			}
 		case uintValue:
 			if w.x == 0 {
 				return Undefined
 			}
-			return MakeInt(v.x / int64(w.x))
+			if y, ok := uintToInt64(w.x); ok {
+				return MakeInt(v.x / y)
+			}
 		case floatValue:
 			if w.x == 0.0 {
 				return Undefined
@@ -349,7 +410,9 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 			if w.x == 0 {
 				return Undefined
 			}
-			return MakeInt(v.x % int64(w.x))
+			if y, ok := uintToInt64(w.x); ok {
+				return MakeInt(v.x % y)
+			}
 		}
 
 	case syntax.And:
@@ -361,17 +424,19 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 		}
 
 	case syntax.Shl:
-		switch w := w.(type) {
-		case intValue:
-			if w.x < 0 {
-				return Undefined
+		if shiftTooLarge(w, v.bits) {
+			return Undefined
+		}
+		if n, ok := shiftCount(w); ok && n < 64 {
+			if x := v.x << n; x>>n == v.x {
+				return MakeInt(x)
 			}
-			return MakeInt(v.x << w.x)
-		case uintValue:
-			return MakeInt(v.x << w.x)
 		}
 
 	case syntax.Shr:
+		if shiftTooLarge(w, v.bits) {
+			return Undefined
+		}
 		switch w := w.(type) {
 		case intValue:
 			if w.x < 0 {
@@ -383,25 +448,31 @@ func (v intValue) Binary(op syntax.Operator, w Value) Value {
 		}
 	}
 
-	return Undefined
+	// the operation overflows, or involves an arbitrary-precision integer
+	return bigBinary(v, op, w)
 }
 
 func (v intValue) Convert(to Kind) Value {
-	if to == v.Kind() {
+	switch to {
+	case v.Kind():
 		return v
+	case TUNTYPEDINT:
+		return intValue{v.x, 0}
+	case TUNTYPEDFLOAT:
+		return floatValue{float64(v.x), 0}
 	}
 
 	if to.IsSigned() {
-		if n := kindbits(to); n > v.bits {
-			return intValue{sext(v.x, v.bits), n}
+		if n := kindbits(to); n > v.width() {
+			return intValue{sext(v.x, v.width()), n}
 		} else {
 			return intValue{sext(v.x, n), n}
 		}
 	}
 
 	if to.IsUnsigned() {
-		if n := kindbits(to); n > v.bits {
-			return uintValue{uint64(sext(v.x, v.bits)), n}
+		if n := kindbits(to); n > v.width() {
+			return uintValue{uint64(sext(v.x, v.width())), n}
 		} else {
 			return uintValue{zext(uint64(v.x), n), n}
 		}
@@ -421,23 +492,18 @@ func (v intValue) Convert(to Kind) Value {
 // uintValue is an unsigned integral value
 type uintValue struct {
 	x    uint64
-	bits int // 8, 16, 32 or 64
+	bits int // 8, 16, 32 or 64, or 0 if untyped
 }
 
-// MakeInt returns a unsigned integer Value with the provided integer.
-//
-// It defaults to a 32-bit integer, but uses a 64-bit integer if x does not fit
-// in just 32 bits.
+// MakeUint returns an untyped integer Value with the provided integer.
 func MakeUint(x uint64) Value {
-	if x > 1<<32-1 {
-		return uintValue{x, 64}
-	} else {
-		return uintValue{x, 32}
-	}
+	return uintValue{x, 0}
 }
 
 func (v uintValue) Kind() Kind {
 	switch v.bits {
+	case 0:
+		return TUNTYPEDINT
 	case 8:
 		return TUINT8
 	case 16:
@@ -447,7 +513,15 @@ func (v uintValue) Kind() Kind {
 	case 64:
 		return TUINT64
 	}
-	panic("unreachable")
+	base.Fatalf("types: invalid bit width %d", v.bits)
+	return TUNDEF // unreachable
+}
+
+func (v uintValue) width() int {
+	if v.bits == 0 {
+		return 64
+	}
+	return v.bits
 }
 
 func (v uintValue) String() string {
@@ -455,20 +529,24 @@ func (v uintValue) String() string {
 }
 
 func (v uintValue) Unary(op syntax.Operator) Value {
+	x, ok := v.x, true
 	switch op {
 	case syntax.Not: // ~v
-		v.x = ^v.x
+		x = ^v.x
 	case syntax.Inc: // ++v or v++
-		v.x += 1
+		x, ok = addUint64(v.x, 1)
 	case syntax.Dec: // --v or v--
-		v.x -= 1
+		x, ok = subUint64(v.x, 1)
 	case syntax.Add: // +v
 		// no-op
 	case syntax.Sub: // -v
-		v.x = -v.x
+		x, ok = subUint64(0, v.x)
 	}
 
-	return MakeUint(v.x)
+	if !ok {
+		return bigValue{new(big.Int).SetUint64(v.x)}.Unary(op)
+	}
+	return MakeUint(x)
 }
 
 func (v uintValue) Binary(op syntax.Operator, w Value) Value {
@@ -572,9 +650,17 @@ func (v uintValue) Binary(op syntax.Operator, w Value) Value {
 	case syntax.Add:
 		switch w := w.(type) {
 		case intValue:
-			return MakeUint(v.x + uint64(w.x))
+			if w.x < 0 {
+				if x, ok := subUint64(v.x, -uint64(w.x)); ok {
+					return MakeUint(x)
+				}
+			} else if x, ok := addUint64(v.x, uint64(w.x)); ok {
+				return MakeUint(x)
+			}
 		case uintValue:
-			return MakeUint(v.x + w.x)
+			if x, ok := addUint64(v.x, w.x); ok {
+				return MakeUint(x)
+			}
 		case floatValue:
 			return MakeFloat(float64(v.x) + w.x)
 		}
@@ -582,9 +668,17 @@ func (v uintValue) Binary(op syntax.Operator, w Value) Value {
 	case syntax.Sub:
 		switch w := w.(type) {
 		case intValue:
-			return MakeUint(v.x - uint64(w.x))
+			if w.x < 0 {
+				if x, ok := addUint64(v.x, -uint64(w.x)); ok {
+					return MakeUint(x)
+				}
+			} else if x, ok := subUint64(v.x, uint64(w.x)); ok {
+				return MakeUint(x)
+			}
 		case uintValue:
-			return MakeUint(v.x - w.x)
+			if x, ok := subUint64(v.x, w.x); ok {
+				return MakeUint(x)
+			}
 		case floatValue:
 			return MakeFloat(float64(v.x) - w.x)
 		}
@@ -608,9 +702,16 @@ func (v uintValue) Binary(op syntax.Operator, w Value) Value {
 	case syntax.Mul:
 		switch w := w.(type) {
 		case intValue:
-			return MakeUint(v.x * uint64(w.x))
+			if w.x < 0 && v.x != 0 {
+				break // negative result
+			}
+			if x, ok := mulUint64(v.x, uint64(w.x)); ok {
+				return MakeUint(x)
+			}
 		case uintValue:
-			return MakeUint(v.x * w.x)
+			if x, ok := mulUint64(v.x, w.x); ok {
+				return MakeUint(x)
+			}
 		case floatValue:
 			return MakeFloat(float64(v.x) * w.x)
 		}
@@ -657,17 +758,19 @@ func (v uintValue) Binary(op syntax.Operator, w Value) Value {
 		}
 
 	case syntax.Shl:
-		switch w := w.(type) {
-		case intValue:
-			if w.x < 0 {
-				return Undefined
+		if shiftTooLarge(w, v.bits) {
+			return Undefined
+		}
+		if n, ok := shiftCount(w); ok && n < 64 {
+			if x := v.x << n; x>>n == v.x {
+				return MakeUint(x)
 			}
-			return MakeUint(v.x << w.x)
-		case uintValue:
-			return MakeUint(v.x << w.x)
 		}
 
 	case syntax.Shr:
+		if shiftTooLarge(w, v.bits) {
+			return Undefined
+		}
 		switch w := w.(type) {
 		case intValue:
 			if w.x < 0 {
@@ -679,25 +782,31 @@ func (v uintValue) Binary(op syntax.Operator, w Value) Value {
 		}
 	}
 
-	return Undefined
+	// the operation overflows, or involves an arbitrary-precision integer
+	return bigBinary(v, op, w)
 }
 
 func (v uintValue) Convert(to Kind) Value {
-	if to == v.Kind() {
+	switch to {
+	case v.Kind():
 		return v
+	case TUNTYPEDINT:
+		return uintValue{v.x, 0}
+	case TUNTYPEDFLOAT:
+		return floatValue{float64(v.x), 0}
 	}
 
 	if to.IsSigned() {
-		if n := kindbits(to); n > v.bits {
-			return intValue{int64(zext(v.x, v.bits)), n}
+		if n := kindbits(to); n > v.width() {
+			return intValue{int64(zext(v.x, v.width())), n}
 		} else {
 			return intValue{sext(int64(v.x), n), n}
 		}
 	}
 
 	if to.IsUnsigned() {
-		if n := kindbits(to); n > v.bits {
-			return uintValue{zext(v.x, v.bits), n}
+		if n := kindbits(to); n > v.width() {
+			return uintValue{zext(v.x, v.width()), n}
 		} else {
 			return uintValue{zext(v.x, n), n}
 		}
@@ -714,35 +823,223 @@ func (v uintValue) Convert(to Kind) Value {
 	return Undefined
 }
 
+// bigValue is an arbitrary-precision integral value. It is only used for
+// integers that do not fit in 64 bits, smaller integers always use intValue or
+// uintValue.
+type bigValue struct {
+	x *big.Int
+}
+
+// maxBigBits limits the size of arbitrary-precision integers, such that huge
+// constants such as 1 << 1e9 cannot exhaust memory.
+const maxBigBits = 4096
+
+// MakeBig returns an integer Value with the provided integer. If x fits in 64
+// bits, the Value is the same as that of MakeInt or MakeUint. Otherwise, it is
+// an arbitrary-precision integer. MakeBig does not retain x.
+func MakeBig(x *big.Int) Value {
+	if x.IsInt64() {
+		return MakeInt(x.Int64())
+	}
+	if x.IsUint64() {
+		return MakeUint(x.Uint64())
+	}
+	if x.BitLen() > maxBigBits {
+		return Undefined
+	}
+	return bigValue{new(big.Int).Set(x)}
+}
+
+func (v bigValue) Kind() Kind {
+	return TUNTYPEDINT
+}
+
+func (v bigValue) String() string {
+	return v.x.String()
+}
+
+func (v bigValue) Unary(op syntax.Operator) Value {
+	x := new(big.Int)
+	switch op {
+	case syntax.Not: // ~v
+		x.Not(v.x)
+	case syntax.Inc: // ++v or v++
+		x.Add(v.x, big.NewInt(1))
+	case syntax.Dec: // --v or v--
+		x.Sub(v.x, big.NewInt(1))
+	case syntax.Add: // +v
+		x.Set(v.x)
+	case syntax.Sub: // -v
+		x.Neg(v.x)
+	default:
+		return Undefined
+	}
+
+	return MakeBig(x)
+}
+
+func (v bigValue) Binary(op syntax.Operator, w Value) Value {
+	if _, ok := w.(floatValue); ok {
+		return v.Convert(TFLOAT64).Binary(op, w)
+	}
+
+	y, ok := toBig(w)
+	if !ok {
+		return Undefined
+	}
+
+	x := new(big.Int)
+	switch op {
+	case syntax.Eql:
+		return MakeBool(v.x.Cmp(y) == 0)
+	case syntax.Neq:
+		return MakeBool(v.x.Cmp(y) != 0)
+	case syntax.Lss:
+		return MakeBool(v.x.Cmp(y) < 0)
+	case syntax.Leq:
+		return MakeBool(v.x.Cmp(y) <= 0)
+	case syntax.Gtr:
+		return MakeBool(v.x.Cmp(y) > 0)
+	case syntax.Geq:
+		return MakeBool(v.x.Cmp(y) >= 0)
+
+	case syntax.Add:
+		x.Add(v.x, y)
+	case syntax.Sub:
+		x.Sub(v.x, y)
+	case syntax.Or:
+		x.Or(v.x, y)
+	case syntax.Xor:
+		x.Xor(v.x, y)
+	case syntax.Mul:
+		if v.x.BitLen()+y.BitLen() > maxBigBits+1 {
+			return Undefined
+		}
+		x.Mul(v.x, y)
+	case syntax.Div:
+		if y.Sign() == 0 {
+			return Undefined
+		}
+		x.Quo(v.x, y)
+	case syntax.Rem:
+		if y.Sign() == 0 {
+			return Undefined
+		}
+		x.Rem(v.x, y)
+	case syntax.And:
+		x.And(v.x, y)
+
+	case syntax.Shl:
+		if y.Sign() < 0 || !y.IsInt64() || int64(v.x.BitLen())+y.Int64() > maxBigBits {
+			return Undefined
+		}
+		x.Lsh(v.x, uint(y.Int64()))
+	case syntax.Shr:
+		if y.Sign() < 0 {
+			return Undefined
+		}
+		n := uint(maxBigBits)
+		if y.IsInt64() && y.Int64() < maxBigBits {
+			n = uint(y.Int64())
+		}
+		x.Rsh(v.x, n)
+
+	default:
+		return Undefined
+	}
+
+	return MakeBig(x)
+}
+
+// Convert converts v to the desired Kind. Contrary to the other integral
+// values, v does not wrap around, but Undefined is returned if v does not fit.
+func (v bigValue) Convert(to Kind) Value {
+	switch to {
+	case TUNTYPEDINT:
+		return v
+	case TUNTYPEDFLOAT:
+		f, _ := new(big.Float).SetInt(v.x).Float64()
+		if math.IsInf(f, 0) {
+			return Undefined
+		}
+		return floatValue{f, 0}
+	}
+
+	if to.IsSigned() {
+		n := kindbits(to)
+		if v.x.IsInt64() && sext(v.x.Int64(), n) == v.x.Int64() {
+			return intValue{v.x.Int64(), n}
+		}
+		return Undefined
+	}
+
+	if to.IsUnsigned() {
+		n := kindbits(to)
+		if v.x.IsUint64() && zext(v.x.Uint64(), n) == v.x.Uint64() {
+			return uintValue{v.x.Uint64(), n}
+		}
+		return Undefined
+	}
+
+	if to.IsFloat() {
+		n := kindbits(to)
+		f, _ := new(big.Float).SetInt(v.x).Float64()
+		if n == 32 {
+			f = float64(float32(f))
+		}
+		if math.IsInf(f, 0) {
+			return Undefined
+		}
+		return floatValue{f, n}
+	}
+
+	return Undefined
+}
+
 // floatValue is a floating-point value
 type floatValue struct {
 	x    float64
-	bits int // 32 or 64
+	bits int // 32 or 64, or 0 if untyped
 }
 
-// MakeFloat returns a floating-point value with the provided float.
-//
-// It defaults toa 32-bit float, but uses a 64-bit float if x is not
-// representable in just 32 bits.
+// MakeFloat returns an untyped floating-point Value with the provided float.
 func MakeFloat(x float64) Value {
-	if float64(float32(x)) == x {
-		return floatValue{x, 32}
-	}
-	return floatValue{x, 64}
+	return floatValue{x, 0}
 }
 
 func (v floatValue) Kind() Kind {
 	switch v.bits {
+	case 0:
+		return TUNTYPEDFLOAT
 	case 32:
 		return TFLOAT32
 	case 64:
 		return TFLOAT64
 	}
-	panic("unreachable")
+	base.Fatalf("types: invalid bit width %d", v.bits)
+	return TUNDEF // unreachable
 }
 
+func (v floatValue) width() int {
+	if v.bits == 0 {
+		return 64
+	}
+	return v.bits
+}
+
+// String returns v in the shortest form which reads back as v, using an
+// exponent for very large or small magnitudes, such as 1e+20. Infinities and
+// NaN have no literal of their own and are spelled +inf, -inf and nan.
 func (v floatValue) String() string {
-	return strconv.FormatFloat(v.x, 'f', -1, v.bits)
+	switch {
+	case math.IsInf(v.x, +1):
+		return "+inf"
+	case math.IsInf(v.x, -1):
+		return "-inf"
+	case math.IsNaN(v.x):
+		return "nan"
+	}
+	return strconv.FormatFloat(v.x, 'g', -1, v.width())
 }
 
 func (v floatValue) Unary(op syntax.Operator) Value {
@@ -761,6 +1058,10 @@ func (v floatValue) Unary(op syntax.Operator) Value {
 }
 
 func (v floatValue) Binary(op syntax.Operator, w Value) Value {
+	if b, ok := w.(bigValue); ok {
+		w = b.Convert(TFLOAT64)
+	}
+
 	switch op {
 	case syntax.Eql:
 		switch w := w.(type) {
@@ -948,8 +1249,17 @@ func (v floatValue) Binary(op syntax.Operator, w Value) Value {
 }
 
 func (v floatValue) Convert(to Kind) Value {
-	if to == v.Kind() {
+	switch to {
+	case v.Kind():
 		return v
+	case TUNTYPEDINT:
+		if v.x != math.Trunc(v.x) || math.IsInf(v.x, 0) {
+			return Undefined
+		}
+		x, _ := big.NewFloat(v.x).Int(nil)
+		return MakeBig(x)
+	case TUNTYPEDFLOAT:
+		return floatValue{v.x, 0}
 	}
 
 	if to.IsSigned() {
@@ -966,13 +1276,499 @@ func (v floatValue) Convert(to Kind) Value {
 		if n := kindbits(to); n == 32 {
 			return floatValue{float64(float32(v.x)), n}
 		} else {
-			return v
+			return floatValue{v.x, n}
 		}
 	}
 
 	return Undefined
 }
 
+// arrayValue is a constant array as a value. Arrays may be large, so their
+// elements are stored sparsely: elements not in elems are zero.
+type arrayValue struct {
+	t     *Type
+	elems map[int]Value
+	zero  Value // Undefined if the element type has no zero value
+}
+
+// MakeArray returns a Value of the array type t with the provided elements,
+// of which there must be as many as the length of t.
+func MakeArray(t *Type, elems []Value) Value {
+	if t.kind != TARRAY || int(t.extra.(*Array).Length) != len(elems) {
+		base.Fatalf("types: MakeArray: %d elements for type %v", len(elems), t)
+	}
+	m := make(map[int]Value, len(elems))
+	for i, elem := range elems {
+		m[i] = elem
+	}
+	return makeArray(t, m)
+}
+
+// makeArray returns a Value of the array type t with the elements at the
+// indices in elems, and zero elements otherwise.
+func makeArray(t *Type, elems map[int]Value) Value {
+	return arrayValue{t, elems, ZeroValue(t.Elem())}
+}
+
+func (arrayValue) Kind() Kind { return TARRAY }
+
+func (v arrayValue) len() int { return int(v.t.extra.(*Array).Length) }
+
+func (v arrayValue) elem(i int) Value {
+	if elem, ok := v.elems[i]; ok {
+		return elem
+	}
+	return v.zero
+}
+
+// String returns v as a compound literal of its type, such as [2]int32{1, 2}.
+// Zero elements are only written if they were provided explicitly, and an
+// element following omitted ones is written with its index, as in
+// [8]int32{1, [6] = 2}.
+func (v arrayValue) String() string {
+	var b strings.Builder
+	b.WriteString(v.t.String())
+	b.WriteByte('{')
+	next := 0
+	for n, i := range slices.Sorted(maps.Keys(v.elems)) {
+		if n > 0 {
+			b.WriteString(", ")
+		}
+		if i != next {
+			fmt.Fprintf(&b, "[%d] = ", i)
+		}
+		b.WriteString(v.elems[i].String())
+		next = i + 1
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func (arrayValue) Unary(syntax.Operator) Value { return Undefined }
+
+// Binary compares v and w element-wise. Arrays only support the operators ==
+// and !=, and only if all elements do.
+func (v arrayValue) Binary(op syntax.Operator, w Value) Value {
+	a, ok := w.(arrayValue)
+	if !ok || v.len() != a.len() || op != syntax.Eql && op != syntax.Neq {
+		return Undefined
+	}
+
+	eq := true
+	ok = v.eachPair(a, func(x, y Value) bool {
+		r := x.Binary(syntax.Eql, y)
+		eq = eq && boolVal(r)
+		return r != Undefined
+	})
+	if !ok {
+		return Undefined
+	}
+	return MakeBool(eq == (op == syntax.Eql))
+}
+
+// eachPair calls f with the elements of v and a, which must be of the same
+// length, at every index either stores explicitly, and once with their zero
+// elements if an index remains. It stops as soon as f returns false, and
+// reports whether f returned true for all pairs.
+func (v arrayValue) eachPair(a arrayValue, f func(x, y Value) bool) bool {
+	n := len(v.elems)
+	for i, x := range v.elems {
+		if !f(x, a.elem(i)) {
+			return false
+		}
+	}
+	for i, y := range a.elems {
+		if _, ok := v.elems[i]; !ok {
+			n++
+			if !f(v.zero, y) {
+				return false
+			}
+		}
+	}
+	return n == v.len() || f(v.zero, a.zero)
+}
+
+func (v arrayValue) Convert(to Kind) Value {
+	if to == v.Kind() {
+		return v
+	}
+	return Undefined
+}
+
+// structValue is a constant struct as a value
+type structValue struct {
+	t      *Type
+	fields []Value
+}
+
+// MakeStruct returns a Value of the struct type t with the provided field
+// values, of which there must be as many as t has fields.
+func MakeStruct(t *Type, fields []Value) Value {
+	if t.kind != TSTRUCT || len(t.extra.(*Struct).Fields) != len(fields) {
+		base.Fatalf("types: MakeStruct: %d fields for type %v", len(fields), t)
+	}
+	return structValue{t, fields}
+}
+
+func (structValue) Kind() Kind { return TSTRUCT }
+
+// String returns v as a compound literal of its type with all fields named,
+// such as Point{x: 1, y: 2}.
+func (v structValue) String() string {
+	var b strings.Builder
+	b.WriteString(v.t.String())
+	b.WriteByte('{')
+	for i, f := range v.fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(v.t.extra.(*Struct).Fields[i].Name)
+		b.WriteString(": ")
+		b.WriteString(f.String())
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func (structValue) Unary(syntax.Operator) Value { return Undefined }
+
+// Binary compares v and w field-wise. Structs only support the operators ==
+// and !=, and only if all fields do.
+func (v structValue) Binary(op syntax.Operator, w Value) Value {
+	s, ok := w.(structValue)
+	if !ok || len(v.fields) != len(s.fields) || op != syntax.Eql && op != syntax.Neq {
+		return Undefined
+	}
+
+	eq := true
+	for i, f := range v.fields {
+		r := f.Binary(syntax.Eql, s.fields[i])
+		if r == Undefined {
+			return Undefined
+		}
+		eq = eq && boolVal(r)
+	}
+	return MakeBool(eq == (op == syntax.Eql))
+}
+
+func (v structValue) Convert(to Kind) Value {
+	if to == v.Kind() {
+		return v
+	}
+	return Undefined
+}
+
+// NumElems returns the number of elements of the array Value v, or the number
+// of fields of the struct Value v. It returns 0 for all other values.
+func NumElems(v Value) int {
+	switch v := v.(type) {
+	case arrayValue:
+		return v.len()
+	case structValue:
+		return len(v.fields)
+	}
+	return 0
+}
+
+// ElemValue returns the element at index i of the array Value v, or the field
+// at index i of the struct Value v, in order of declaration. v must be an
+// array or struct, and i must be in range.
+func ElemValue(v Value, i int) Value {
+	switch v := v.(type) {
+	case arrayValue:
+		return v.elem(i)
+	case structValue:
+		return v.fields[i]
+	}
+	base.Fatalf("types: ElemValue of %v", v)
+	return nil
+}
+
+// optionValue is the absent value of an option type as a value. Present
+// values of option types are represented by values of the element type.
+type optionValue struct {
+	t *Type
+}
+
+// MakeNone returns the absent Value of the option type t.
+func MakeNone(t *Type) Value {
+	if t.kind != TOPTION {
+		base.Fatalf("types: MakeNone of %v", t)
+	}
+	return optionValue{t}
+}
+
+func (optionValue) Kind() Kind                  { return TOPTION }
+func (optionValue) String() string              { return "none" }
+func (optionValue) Unary(syntax.Operator) Value { return Undefined }
+
+// Binary compares v and w. Absent values are equal to each other, and only
+// support the operators == and !=.
+func (v optionValue) Binary(op syntax.Operator, w Value) Value {
+	if _, ok := w.(optionValue); !ok || op != syntax.Eql && op != syntax.Neq {
+		return Undefined
+	}
+	return MakeBool(op == syntax.Eql)
+}
+
+func (v optionValue) Convert(to Kind) Value {
+	if to == v.Kind() {
+		return v
+	}
+	return Undefined
+}
+
+// ConvertValue converts the constant v to the type dst, as by an explicit
+// conversion: numeric values truncate and wrap around, and booleans convert to
+// integers as 1 and 0. Constants of compound types only convert to identical
+// types. ConvertValue returns an error if v cannot be converted to dst or if
+// the result is not representable.
+func ConvertValue(v Value, dst *Type) (Value, error) {
+	if isNumeric(dst) || isBoolean(dst) {
+		if w := v.Convert(valueKind(dst.kind)); w != Undefined {
+			return w, nil
+		}
+		return Undefined, fmt.Errorf("cannot convert %s to type %s", v, dst)
+	}
+
+	var t *Type
+	switch v := v.(type) {
+	case arrayValue:
+		t = v.t
+	case structValue:
+		t = v.t
+	case optionValue:
+		t = v.t
+	case typeValue:
+		t = Types[TTYPE]
+	}
+	if t == nil || !Identical(t, dst) {
+		return Undefined, fmt.Errorf("cannot convert %s to type %s", v, dst)
+	}
+	return v, nil
+}
+
+// ZeroValue returns the zero value of type T, which variables of type T are
+// initialized with: 0 for numeric types, false for bool, none for option
+// types, and arrays and structs whose elements are zero. It returns Undefined
+// if values of type T cannot be constant, such as for pointers and procedures,
+// and for arrays and structs with elements of such types.
+func ZeroValue(T *Type) Value {
+	switch k := T.kind; {
+	case k == TBOOL:
+		return MakeBool(false).Convert(k)
+	case k.IsIntegral():
+		return MakeInt(0).Convert(valueKind(k))
+	case k.IsFloat():
+		return MakeFloat(0).Convert(k)
+	case k == TOPTION:
+		return MakeNone(T)
+	case k == TARRAY:
+		a := T.extra.(*Array)
+		if ZeroValue(a.Elem) == Undefined {
+			return Undefined
+		}
+		return makeArray(T, nil)
+	case k == TSTRUCT:
+		fields := T.extra.(*Struct).Fields
+		vals := make([]Value, len(fields))
+		for i, f := range fields {
+			vals[i] = ZeroValue(f.Type)
+			if vals[i] == Undefined {
+				return Undefined
+			}
+		}
+		return MakeStruct(T, vals)
+	}
+	return Undefined
+}
+
+// LiteralValue returns the Value of a literal of the provided kind, as
+// scanned by package syntax. If the literal is not representable, or if it is
+// a string literal, Undefined is returned.
+func LiteralValue(kind syntax.Literal, lit string) Value {
+	switch kind {
+	case syntax.Int:
+		if x, err := strconv.ParseInt(lit, 0, 64); err == nil {
+			return MakeInt(x)
+		}
+		if x, err := strconv.ParseUint(lit, 0, 64); err == nil {
+			return MakeUint(x)
+		}
+		if x, ok := new(big.Int).SetString(lit, 0); ok {
+			return MakeBig(x)
+		}
+
+	case syntax.Float:
+		if x, err := strconv.ParseFloat(lit, 64); err == nil {
+			return MakeFloat(x)
+		}
+
+	case syntax.Char:
+		if len(lit) < 2 {
+			break
+		}
+		r, _, tail, err := strconv.UnquoteChar(lit[1:len(lit)-1], '\'')
+		if err == nil && tail == "" {
+			return MakeInt(int64(r))
+		}
+	}
+
+	return Undefined
+}
+
+// Equal reports whether v and w are exactly equal, meaning that they have both
+// the same Kind and the same value. Contrary to Binary with syntax.Eql, values
+// of different Kinds are never Equal. Undefined is not Equal to any Value,
+// including itself, and types are Equal if they are [Identical].
+func Equal(v, w Value) bool {
+	if v.Kind() != w.Kind() {
+		return false
+	}
+
+	switch v := v.(type) {
+	case typeValue:
+		return Identical(v.t, w.(typeValue).t)
+	case boolValue:
+		return v.b == w.(boolValue).b
+	case intValue, uintValue, bigValue:
+		// untyped integers may be represented by any of these
+		return boolVal(v.Binary(syntax.Eql, w))
+	case floatValue:
+		return v.x == w.(floatValue).x
+	case optionValue:
+		// absent values are equal if their option types are
+		return Identical(v.t, w.(optionValue).t)
+	case arrayValue:
+		a := w.(arrayValue)
+		return Identical(v.t, a.t) && v.eachPair(a, Equal)
+	case structValue:
+		s := w.(structValue)
+		if !Identical(v.t, s.t) {
+			return false
+		}
+		for i, f := range v.fields {
+			if !Equal(f, s.fields[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false // undefValue
+}
+
+// DefaultType returns the type an untyped Value assumes if its type is not
+// otherwise determined, such as in a variable declaration without a type. For
+// typed Values, DefaultType returns the type of the Value.
+//
+// Untyped integers default to int32, or int64 if they do not fit in just 32
+// bits. Untyped floats default to float64.
+func DefaultType(v Value) *Type {
+	switch k := v.Kind(); k {
+	case TUNTYPEDBOOL:
+		return Types[TBOOL]
+	case TUNTYPEDINT:
+		if x, ok := int64Val(v); ok && x >= math.MinInt32 && x <= math.MaxInt32 {
+			return Types[TINT32]
+		}
+		return Types[TINT64]
+	case TUNTYPEDFLOAT:
+		return Types[TFLOAT64]
+	default:
+		return Types[k]
+	}
+}
+
+// FormatValue returns a string representation of v like String, but formats
+// integral values in the provided base, which must be 2, 8, 10 or 16. Other
+// than in base 10, the digits are prefixed with 0b, 0o or 0x, as in integer
+// literals. Values that are not integral, including floats, are formatted as
+// by String.
+func FormatValue(v Value, b int) string {
+	var prefix string
+	switch b {
+	case 2:
+		prefix = "0b"
+	case 8:
+		prefix = "0o"
+	case 10:
+		// no prefix
+	case 16:
+		prefix = "0x"
+	default:
+		base.Fatalf("types: FormatValue: invalid base %d", b)
+	}
+
+	x, ok := toBig(v)
+	if !ok {
+		return v.String()
+	}
+	if x.Sign() < 0 {
+		return "-" + prefix + new(big.Int).Neg(x).Text(b)
+	}
+	return prefix + x.Text(b)
+}
+
+// MinValue returns the smallest value representable by the numeric kind k, as
+// a Value of that kind. For floats, this is the most negative finite value.
+// Untyped kinds have no bounds, and MinValue returns Undefined for them, as
+// well as for non-numeric kinds.
+func MinValue(k Kind) Value {
+	switch k = valueKind(k); {
+	case k.IsUntyped():
+		return Undefined
+	case k.IsSigned():
+		n := kindbits(k)
+		return intValue{-1 << (n - 1), n}
+	case k.IsUnsigned():
+		return uintValue{0, kindbits(k)}
+	case k.IsFloat():
+		max := MaxValue(k).(floatValue)
+		return floatValue{-max.x, max.bits}
+	}
+	return Undefined
+}
+
+// MaxValue returns the largest value representable by the numeric kind k, as
+// a Value of that kind. For floats, this is the largest finite value. Untyped
+// kinds have no bounds, and MaxValue returns Undefined for them, as well as
+// for non-numeric kinds.
+func MaxValue(k Kind) Value {
+	switch k = valueKind(k); {
+	case k.IsUntyped():
+		return Undefined
+	case k.IsSigned():
+		n := kindbits(k)
+		return intValue{1<<(n-1) - 1, n}
+	case k.IsUnsigned():
+		n := kindbits(k)
+		return uintValue{math.MaxUint64 >> (64 - n), n}
+	case k.IsFloat():
+		if n := kindbits(k); n == 32 {
+			return floatValue{math.MaxFloat32, n}
+		}
+		return floatValue{math.MaxFloat64, 64}
+	}
+	return Undefined
+}
+
+// Representable reports whether the value v can be converted to the kind k
+// without changing it. For numeric kinds, this means without overflowing, and
+// conversions to integers must also be exact, whereas conversions to floats
+// may round.
+func Representable(v Value, k Kind) bool {
+	w := v.Convert(valueKind(k))
+	switch {
+	case w == Undefined:
+		return false
+	case k.IsFloat():
+		return !math.IsInf(w.(floatValue).x, 0)
+	default:
+		return boolVal(w.Binary(syntax.Eql, v))
+	}
+}
+
 // ----------------------------------------------------------------------------
 // Utilities
 
@@ -988,6 +1784,107 @@ func zext(x uint64, n int) uint64 {
 	return x & mask
 }
 
+// The following helpers perform checked integer arithmetic. They return the
+// result along with whether the operation did not overflow.
+
+func addInt64(x, y int64) (int64, bool) {
+	z := x + y
+	return z, (x^z)&(y^z) >= 0 // overflow iff x and y have the same sign, but z does not
+}
+
+func subInt64(x, y int64) (int64, bool) {
+	z := x - y
+	return z, (x^y)&(x^z) >= 0 // overflow iff x and y have different signs, and z and x too
+}
+
+func mulInt64(x, y int64) (int64, bool) {
+	hi, lo := bits.Mul64(absInt64(x), absInt64(y))
+	if hi != 0 {
+		return 0, false
+	}
+	if (x < 0) != (y < 0) {
+		return -int64(lo), lo <= 1<<63
+	}
+	return int64(lo), lo <= math.MaxInt64
+}
+
+func divInt64(x, y int64) (int64, bool) {
+	return x / y, x != math.MinInt64 || y != -1 // overflow iff -MinInt64
+}
+
+func addUint64(x, y uint64) (uint64, bool) {
+	z, carry := bits.Add64(x, y, 0)
+	return z, carry == 0
+}
+
+func subUint64(x, y uint64) (uint64, bool) {
+	z, borrow := bits.Sub64(x, y, 0)
+	return z, borrow == 0
+}
+
+func mulUint64(x, y uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(x, y)
+	return lo, hi == 0
+}
+
+func uintToInt64(x uint64) (int64, bool) {
+	return int64(x), x <= math.MaxInt64
+}
+
+func absInt64(x int64) uint64 {
+	if x < 0 {
+		return -uint64(x)
+	}
+	return uint64(x)
+}
+
+// toBig returns the value of an integral Value as a big.Int, and whether v
+// is in fact integral.
+func toBig(v Value) (*big.Int, bool) {
+	switch v := v.(type) {
+	case intValue:
+		return big.NewInt(v.x), true
+	case uintValue:
+		return new(big.Int).SetUint64(v.x), true
+	case bigValue:
+		return v.x, true
+	}
+	return nil, false
+}
+
+// bigBinary performs the binary operation on the integral values v and w in
+// arbitrary precision. It is used once an operation overflows 64 bits.
+func bigBinary(v Value, op syntax.Operator, w Value) Value {
+	x, ok := toBig(v)
+	if !ok {
+		return Undefined
+	}
+	return bigValue{x}.Binary(op, w)
+}
+
+// shiftCount returns the value of the integral Value v as a shift count, and
+// whether it is a valid shift count.
+func shiftCount(v Value) (uint64, bool) {
+	switch v := v.(type) {
+	case intValue:
+		return uint64(v.x), v.x >= 0
+	case uintValue:
+		return v.x, true
+	}
+	return 0, false
+}
+
+// shiftTooLarge reports whether w is too large a count for shifting a typed
+// value of the given bit width, that is at least as large as the width itself.
+// Untyped values have no width, and may be shifted by any count.
+func shiftTooLarge(w Value, bits int) bool {
+	if bits == 0 {
+		return false
+	}
+	n, ok := shiftCount(w)
+	return !ok || n >= uint64(bits)
+}
+
 func kindbits(k Kind) int {
 	switch k {
 	case TINT8, TUINT8:
@@ -1013,3 +1910,21 @@ func floatCanUint64(f float64) bool {
 		f >= 0 &&
 		f <= float64(math.MaxUint64)
 }
+
+// boolVal returns the value of a boolean Value.
+func boolVal(v Value) bool {
+	b, _ := v.(boolValue)
+	return b.b
+}
+
+// int64Val returns the value of an integral Value as an int64, and whether
+// it is representable as such.
+func int64Val(v Value) (int64, bool) {
+	switch v := v.(type) {
+	case intValue:
+		return v.x, true
+	case uintValue:
+		return uintToInt64(v.x)
+	}
+	return 0, false
+}
@@ -0,0 +1,460 @@
+// Copyright (c) 2025 Thomas Cunningham. All rights reserved.
+// Use of this source code is governed by an MIT license that
+// can be found in the LICENSE file.
+
+package types
+
+import (
+	"cobalt/syntax"
+	"math"
+	"testing"
+)
+
+func TestAggregateValues(t *testing.T) {
+	int32Type := Types[TINT32]
+	elems := []Value{MakeInt(1).Convert(TINT32), MakeInt(2).Convert(TINT32), MakeInt(3).Convert(TINT32)}
+	arr := MakeArray(NewArray(int32Type, 3), elems)
+	if got, want := arr.String(), "[3]int32{1, 2, 3}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if n := NumElems(arr); n != 3 {
+		t.Errorf("NumElems(%s) = %d, want 3", arr, n)
+	}
+	for i, want := range elems {
+		if got := ElemValue(arr, i); !Equal(got, want) {
+			t.Errorf("ElemValue(%s, %d) = %s, want %s", arr, i, got, want)
+		}
+	}
+	// zero elements are omitted unless provided explicitly
+	z := MakeInt(0).Convert(TINT32)
+	zeros, zero := MakeArray(NewArray(int32Type, 3), []Value{z, z, z}), ZeroValue(NewArray(int32Type, 3))
+	if got, want := zeros.String(), "[3]int32{0, 0, 0}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := zero.String(), "[3]int32{}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if !Equal(zero, zeros) || !boolVal(zero.Binary(syntax.Eql, zeros)) {
+		t.Errorf("%s and %s differ, want them equal", zero, zeros)
+	}
+	if got := arr.Binary(syntax.Add, arr); got != Undefined {
+		t.Errorf("%s + %s = %s, want Undefined", arr, arr, got)
+	}
+
+	st := MakeStruct(NewStruct([]*Field{{Name: "x", Type: int32Type}, {Name: "y", Type: Types[TBOOL]}}),
+		[]Value{MakeInt(1).Convert(TINT32), MakeBool(true)})
+	if got, want := st.String(), "struct{x: int32; y: bool}{x: 1, y: true}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if n := NumElems(st); n != 2 {
+		t.Errorf("NumElems(%s) = %d, want 2", st, n)
+	}
+	if got := ElemValue(st, 1); got.String() != "true" {
+		t.Errorf("ElemValue(%s, 1) = %s, want true", st, got)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	i32 := Types[TINT32]
+	for _, test := range []struct {
+		T    *Type
+		want string
+		kind Kind
+	}{
+		{i32, "0", TINT32},
+		{Types[TBOOL], "false", TBOOL},
+		{Types[TFLOAT64], "0", TFLOAT64},
+		{Types[TUINTPTR], "0", valueKind(TUINTPTR)},
+		{NewOption(i32), "none", TOPTION},
+		{NewStruct([]*Field{{Name: "a", Type: Types[TINT8]}, {Name: "b", Type: NewOption(Types[TBOOL])}}),
+			"struct{a: int8; b: ?bool}{a: 0, b: none}", TSTRUCT},
+		// pointers and procedures have no constant values
+		{NewPointer(i32, false), "<undefined>", TUNDEF},
+		{NewSignature(nil, nil), "<undefined>", TUNDEF},
+		{NewArray(NewPointer(i32, false), 2), "<undefined>", TUNDEF},
+	} {
+		v := ZeroValue(test.T)
+		if got := v.String(); got != test.want {
+			t.Errorf("ZeroValue(%s) = %s, want %s", test.T, got, test.want)
+		}
+		if got := v.Kind(); got != test.kind {
+			t.Errorf("ZeroValue(%s) is of kind %v, want %v", test.T, got, test.kind)
+		}
+	}
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(x, y int64) (int64, bool)
+		x, y int64
+		ok   bool
+	}{
+		{"add", addInt64, math.MaxInt64, 1, false},
+		{"add", addInt64, math.MinInt64, -1, false},
+		{"add", addInt64, math.MaxInt64, -1, true},
+		{"sub", subInt64, math.MinInt64, 1, false},
+		{"sub", subInt64, 0, math.MinInt64, false},
+		{"sub", subInt64, -1, math.MinInt64, true},
+		{"mul", mulInt64, math.MaxInt64, 2, false},
+		{"mul", mulInt64, math.MinInt64, -1, false},
+		{"mul", mulInt64, math.MinInt64, 1, true},
+		{"mul", mulInt64, math.MaxInt32, math.MaxInt32, true},
+		{"div", divInt64, math.MinInt64, -1, false},
+		{"div", divInt64, math.MinInt64, 1, true},
+		{"div", divInt64, math.MaxInt64, -1, true},
+	} {
+		z, ok := test.f(test.x, test.y)
+		if ok != test.ok {
+			t.Errorf("%s(%d, %d) = %d, %v, want ok = %v", test.name, test.x, test.y, z, ok, test.ok)
+		}
+	}
+
+	// overflowing results are not wrapped around, so they are no longer
+	// representable by int64
+	v := MakeInt(math.MaxInt64).Binary(syntax.Add, MakeInt(1))
+	if got, want := v.String(), "9223372036854775808"; got != want {
+		t.Errorf("MaxInt64 + 1 = %s, want %s", got, want)
+	}
+	if Representable(v, TINT64) {
+		t.Errorf("MaxInt64 + 1 is representable by int64")
+	}
+
+	// MaxInt32 * MaxInt32 fits 64 bits, but not 32 bits
+	max32 := MakeInt(math.MaxInt32).Convert(TINT32)
+	v = max32.Binary(syntax.Mul, max32)
+	if got, want := v.String(), "4611686014132420609"; got != want {
+		t.Errorf("MaxInt32 * MaxInt32 = %s, want %s", got, want)
+	}
+	if got := v.Convert(TINT64); got.String() != "4611686014132420609" {
+		t.Errorf("int64(MaxInt32 * MaxInt32) = %s", got)
+	}
+	if !Representable(v, TINT64) || Representable(v, TINT32) {
+		t.Errorf("MaxInt32 * MaxInt32 must be representable by int64 only")
+	}
+
+	// in-range operations are unaffected
+	if got := MakeInt(2).Binary(syntax.Mul, MakeInt(-3)); !Equal(got, MakeInt(-6)) {
+		t.Errorf("2 * -3 = %s, want -6", got)
+	}
+}
+
+func TestBigValues(t *testing.T) {
+	v := MakeInt(1).Binary(syntax.Shl, MakeInt(100))
+	if got, want := v.String(), "1267650600228229401496703205376"; got != want {
+		t.Errorf("1 << 100 = %s, want %s", got, want)
+	}
+	if got := v.Convert(TINT64); got != Undefined {
+		t.Errorf("int64(1 << 100) = %s, want Undefined", got)
+	}
+
+	// (1 << 100) >> 90 fits 64 bits again
+	w := v.Binary(syntax.Shr, MakeInt(90))
+	if got := w.Convert(TINT64); got.Kind() != TINT64 || got.String() != "1024" {
+		t.Errorf("int64((1 << 100) >> 90) = %s (%v), want 1024 (int64)", got, got.Kind())
+	}
+	if !Equal(w, MakeInt(1024)) {
+		t.Errorf("(1 << 100) >> 90 = %s, want the plain integer 1024", w)
+	}
+}
+
+func TestUntypedKinds(t *testing.T) {
+	if k := MakeInt(1).Binary(syntax.Add, MakeFloat(2.0)).Kind(); k != TUNTYPEDFLOAT {
+		t.Errorf("1 + 2.0 is of kind %v, want %v", k, TUNTYPEDFLOAT)
+	}
+
+	for _, test := range []struct {
+		val  Value
+		want Kind
+	}{
+		{MakeBool(true), TBOOL},
+		{MakeInt(1), TINT32},
+		{MakeInt(1 << 40), TINT64},
+		{MakeFloat(1.5), TFLOAT64},
+		{MakeInt(1).Convert(TUINT8), TUINT8},
+	} {
+		if got := DefaultType(test.val); got != Types[test.want] {
+			t.Errorf("DefaultType(%s) = %v, want %v", test.val, got, Types[test.want])
+		}
+	}
+}
+
+func TestBitWidths(t *testing.T) {
+	// every numeric kind maps to a valid bit width and back; pointer-sized
+	// kinds are represented by the kind of their size
+	for k := TINT8; k <= TFLOAT64; k++ {
+		k := valueKind(k)
+		for _, v := range []Value{MakeInt(1), MakeUint(1), MakeFloat(1)} {
+			if got := v.Convert(k).Kind(); got != k {
+				t.Errorf("%s converted to %v is of kind %v", v, k, got)
+			}
+		}
+	}
+	for _, test := range []struct {
+		val  Value
+		want Kind
+	}{
+		{MakeInt(1), TUNTYPEDINT},
+		{MakeUint(1), TUNTYPEDINT},
+		{MakeFloat(1), TUNTYPEDFLOAT},
+	} {
+		if got := test.val.Kind(); got != test.want {
+			t.Errorf("%s is of kind %v, want %v", test.val, got, test.want)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	i32, i64 := MakeInt(1).Convert(TINT32), MakeInt(1).Convert(TINT64)
+	none32, none64 := MakeNone(NewOption(Types[TINT32])), MakeNone(NewOption(Types[TINT64]))
+	for _, test := range []struct {
+		v, w       Value
+		equal, eql bool
+	}{
+		{i32, i32, true, true},
+		{i32, i64, false, true}, // == folds to true, but the kinds differ
+		{i32, MakeInt(2).Convert(TINT32), false, false},
+		{MakeInt(1), MakeInt(1), true, true},
+		{MakeInt(1), MakeUint(1), true, true}, // both untyped integers
+		{MakeFloat(0.5), MakeFloat(0.5), true, true},
+		{MakeBool(true), MakeBool(true), true, true},
+		{MakeBool(true), MakeBool(true).Convert(TBOOL), false, true},
+		{none32, MakeNone(NewOption(Types[TINT32])), true, true},
+		{none32, none64, false, true}, // absent values, but of different types
+	} {
+		if got := Equal(test.v, test.w); got != test.equal {
+			t.Errorf("Equal(%s, %s) = %v, want %v", test.v, test.w, got, test.equal)
+		}
+		if got := boolVal(test.v.Binary(syntax.Eql, test.w)); got != test.eql {
+			t.Errorf("%s == %s is %v, want %v", test.v, test.w, got, test.eql)
+		}
+	}
+
+	if Equal(Undefined, Undefined) {
+		t.Errorf("Equal(Undefined, Undefined) = true, want false")
+	}
+	a, b := MakeType(NewPointer(Types[TINT32], false)), MakeType(NewPointer(Types[TINT32], false))
+	if !Equal(a, b) {
+		t.Errorf("Equal(%s, %s) = false, want true", a, b)
+	}
+	if c := MakeType(Types[TINT32]); Equal(a, c) {
+		t.Errorf("Equal(%s, %s) = true, want false", a, c)
+	}
+	if Equal(none32, i32) || Equal(i32, none32) {
+		t.Errorf("Equal(%s, %s) = true, want false", none32, i32)
+	}
+}
+
+func TestBoolConversion(t *testing.T) {
+	for _, test := range []struct {
+		b    bool
+		to   Kind
+		want string
+	}{
+		{true, TINT32, "1"},
+		{false, TINT32, "0"},
+		{true, TUINT8, "1"},
+		{false, TUINT8, "0"},
+		{true, TUNTYPEDINT, "1"},
+	} {
+		got := MakeBool(test.b).Convert(test.to)
+		if got.String() != test.want || got.Kind() != test.to {
+			t.Errorf("%v converted to %v = %s of kind %v, want %s", test.b, test.to, got, got.Kind(), test.want)
+		}
+	}
+
+	// floating-point values are not integers, and integers are not booleans
+	for _, val := range []Value{MakeBool(true).Convert(TFLOAT32), MakeBool(false).Convert(TFLOAT32), MakeInt(1).Convert(TBOOL)} {
+		if val != Undefined {
+			t.Errorf("got %s, want Undefined", val)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	for _, test := range []struct {
+		k        Kind
+		min, max string
+	}{
+		{TINT8, "-128", "127"},
+		{TINT16, "-32768", "32767"},
+		{TINT32, "-2147483648", "2147483647"},
+		{TINT64, "-9223372036854775808", "9223372036854775807"},
+		{TUINT8, "0", "255"},
+		{TUINT16, "0", "65535"},
+		{TUINT32, "0", "4294967295"},
+		{TUINT64, "0", "18446744073709551615"},
+		{TFLOAT32, "-3.4028235e+38", "3.4028235e+38"},
+		{TFLOAT64, "-1.7976931348623157e+308", "1.7976931348623157e+308"},
+	} {
+		if got := MinValue(test.k); got.String() != test.min || got.Kind() != test.k {
+			t.Errorf("MinValue(%v) = %s of kind %v, want %s", test.k, got, got.Kind(), test.min)
+		}
+		if got := MaxValue(test.k); got.String() != test.max || got.Kind() != test.k {
+			t.Errorf("MaxValue(%v) = %s of kind %v, want %s", test.k, got, got.Kind(), test.max)
+		}
+	}
+	for _, k := range []Kind{TUNTYPEDINT, TUNTYPEDFLOAT, TBOOL} {
+		if MinValue(k) != Undefined || MaxValue(k) != Undefined {
+			t.Errorf("%v has bounds, want none", k)
+		}
+	}
+}
+
+func TestRepresentable(t *testing.T) {
+	for _, test := range []struct {
+		v    Value
+		k    Kind
+		want bool
+	}{
+		{MakeInt(255), TUINT8, true},
+		{MakeInt(300), TUINT8, false},
+		{MakeInt(-1), TUINT8, false},
+		{MakeInt(-128), TINT8, true},
+		{MakeInt(-129), TINT8, false},
+		{MakeUint(math.MaxUint64), TUINT64, true},
+		{MakeUint(math.MaxUint64), TINT64, false},
+		{MakeFloat(1.5), TINT32, false},
+		{MakeFloat(2), TINT32, true},
+		{MakeFloat(1e300), TFLOAT32, false},
+		{MakeFloat(0.1), TFLOAT32, true}, // rounding is allowed
+		{MakeBool(true), TINT32, false},  // converts, but to a different value
+	} {
+		if got := Representable(test.v, test.k); got != test.want {
+			t.Errorf("Representable(%s, %v) = %v, want %v", test.v, test.k, got, test.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	big := MakeInt(1).Binary(syntax.Shl, MakeInt(70))
+	for _, test := range []struct {
+		v    Value
+		base int
+		want string
+	}{
+		{MakeInt(255), 16, "0xff"},
+		{MakeInt(255), 2, "0b11111111"},
+		{MakeInt(255), 8, "0o377"},
+		{MakeInt(255), 10, "255"},
+		{MakeInt(-10), 16, "-0xa"},
+		{MakeUint(math.MaxUint64), 16, "0xffffffffffffffff"},
+		{MakeInt(200).Convert(TUINT8), 16, "0xc8"},
+		{big, 16, "0x400000000000000000"},
+		{MakeFloat(1.5), 16, "1.5"},
+		{MakeBool(true), 2, "true"},
+	} {
+		if got := FormatValue(test.v, test.base); got != test.want {
+			t.Errorf("FormatValue(%s, %d) = %s, want %s", test.v, test.base, got, test.want)
+		}
+	}
+}
+
+func TestFloatString(t *testing.T) {
+	for _, test := range []struct {
+		v    Value
+		want string
+	}{
+		{MakeFloat(1e20), "1e+20"},
+		{MakeFloat(0.1), "0.1"},
+		{MakeFloat(1.5).Convert(TFLOAT32), "1.5"},
+		{MakeFloat(0.1).Convert(TFLOAT32), "0.1"}, // the shortest float32 spelling
+		{MakeFloat(math.Inf(1)), "+inf"},
+		{MakeFloat(math.Inf(-1)), "-inf"},
+		{MakeFloat(math.NaN()), "nan"},
+	} {
+		if got := test.v.String(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestStructEquality(t *testing.T) {
+	T := NewStruct([]*Field{{Name: "x", Type: Types[TINT32]}, {Name: "y", Type: Types[TBOOL]}})
+	one, two := MakeInt(1).Convert(TINT32), MakeInt(2).Convert(TINT32)
+	yes := MakeBool(true).Convert(TBOOL)
+	a, b, c := MakeStruct(T, []Value{one, yes}), MakeStruct(T, []Value{one, yes}), MakeStruct(T, []Value{two, yes})
+	for _, test := range []struct {
+		v, w Value
+		eq   bool
+	}{
+		{a, b, true},
+		{a, c, false},
+	} {
+		if got := boolVal(test.v.Binary(syntax.Eql, test.w)); got != test.eq {
+			t.Errorf("%s == %s is %v, want %v", test.v, test.w, got, test.eq)
+		}
+		if got := boolVal(test.v.Binary(syntax.Neq, test.w)); got == test.eq {
+			t.Errorf("%s != %s is %v, want %v", test.v, test.w, got, !test.eq)
+		}
+		if got := Equal(test.v, test.w); got != test.eq {
+			t.Errorf("Equal(%s, %s) = %v, want %v", test.v, test.w, got, test.eq)
+		}
+	}
+	if got := a.Binary(syntax.Lss, b); got != Undefined {
+		t.Errorf("%s < %s = %s, want Undefined", a, b, got)
+	}
+}
+
+func TestCompositeString(t *testing.T) {
+	mod := wantErrors(t, "type Point struct{x: int32; y: int32;}; const p = (Point){.x = 1, .y = 2}; "+
+		"const a = ([2]int32){1, 2}; const n = ([2]?int32){none}; const q = ([2]Point){{.x = 1}, p}; "+
+		"const s = (struct{a: [2]int8; b: ?int8;}){.a = {5}};")
+	wantConsts(t, mod,
+		"p", "Point{x: 1, y: 2}",
+		"a", "[2]int32{1, 2}",
+		"n", "[2]?int32{none}",
+		"q", "[2]Point{Point{x: 1, y: 0}, Point{x: 1, y: 2}}",
+		"s", "struct{a: [2]int8; b: ?int8}{a: [2]int8{5}, b: none}")
+}
+
+func TestConvertValue(t *testing.T) {
+	i32 := Types[TINT32]
+	arr := NewArray(i32, 1)
+	a := MakeArray(arr, []Value{MakeInt(1).Convert(TINT32)})
+	for _, test := range []struct {
+		v    Value
+		dst  *Type
+		want string // or the error
+	}{
+		{MakeFloat(3.9), i32, "3"}, // floats truncate
+		{MakeFloat(-3.9), i32, "-3"},
+		{MakeInt(300), Types[TUINT8], "44"}, // integers wrap around
+		{MakeBool(true), i32, "1"},
+		{a, NewArray(i32, 1), "[1]int32{1}"},
+		{MakeBool(true), NewPointer(i32, false), "cannot convert true to type *int32"},
+		{MakeInt(1), arr, "cannot convert 1 to type [1]int32"},
+		{a, NewArray(Types[TINT64], 1), "cannot convert [1]int32{1} to type [1]int64"},
+	} {
+		v, err := ConvertValue(test.v, test.dst)
+		got := v.String()
+		if err != nil {
+			got = err.Error()
+		}
+		if got != test.want {
+			t.Errorf("ConvertValue(%s, %s) = %s, want %s", test.v, test.dst, got, test.want)
+		}
+	}
+}
+
+func TestShiftBounds(t *testing.T) {
+	for _, test := range []struct {
+		x    Value
+		op   syntax.Operator
+		n    int64
+		want string
+	}{
+		{MakeInt(1).Convert(TUINT8), syntax.Shl, 7, "128"},
+		{MakeInt(1).Convert(TUINT8), syntax.Shl, 8, "<undefined>"},
+		{MakeUint(1).Convert(TUINT64), syntax.Shl, 64, "<undefined>"},
+		{MakeInt(1).Convert(TINT64), syntax.Shl, 64, "<undefined>"},
+		{MakeInt(1).Convert(TINT32), syntax.Shr, 32, "<undefined>"},
+		{MakeInt(1).Convert(TINT32), syntax.Shl, -1, "<undefined>"},
+		// untyped constants have no width to exceed
+		{MakeInt(1), syntax.Shl, 64, "18446744073709551616"},
+	} {
+		if got := test.x.Binary(test.op, MakeInt(test.n)).String(); got != test.want {
+			t.Errorf("%s %s %d = %s, want %s", test.x, test.op, test.n, got, test.want)
+		}
+	}
+}